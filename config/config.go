@@ -1,5 +1,94 @@
 package config
 
+import "time"
+
 type NodeConfig struct {
+	Moniker string // Human-readable name of the node, at most MaxMonikerLength bytes long
+
+	// Mode is one of ModeFull, ModeAggregator or ModeArchive. If it's empty, ModeFull is used.
+	Mode string
+
 	P2P P2PConfig
+
+	DALayer  string // Name of DA layer client, as registered in da/registry
+	DAConfig string // DA layer client specific configuration, passed to Init
+
+	// NamespaceID is the namespace ID of the chain. If it's zero, it's derived from chain ID (see types.ChainNamespaceID).
+	NamespaceID [8]byte
+	// NamespaceEpochLength is the number of blocks after which namespace ID is rotated (see types.EpochNamespaceID).
+	// If it's zero, NamespaceID is used for all blocks.
+	NamespaceEpochLength uint64
+
+	DAStartTimeout time.Duration // Maximum time to wait for DA layer client to start
+	DAStopTimeout  time.Duration // Maximum time to wait for DA layer client to stop
+
+	// DAConfirmationDepth is the number of DA layer confirmations after which submitted block is considered final.
+	DAConfirmationDepth uint64
+	// DAPollInterval is the interval of polling DA layer for block confirmations and, in non-aggregator modes, for new
+	// blocks of the chain. Zero means DefaultDAPollInterval; negative interval is invalid.
+	DAPollInterval time.Duration
+	// DAVerifySubmissions enables retrieving every submitted block back from DA layer, once it has
+	// DAConfirmationDepth confirmations, to verify that it was included without modifications.
+	DAVerifySubmissions bool
+	// DAVerifyTimeout is the maximum time to wait for confirmations of submitted block, before its verification fails.
+	DAVerifyTimeout time.Duration
+	// DAAuditLog is the path of file to which every block submission attempt is appended (see da.AuditingClient).
+	// If it's empty, submissions are not audited.
+	DAAuditLog string
+
+	// ShutdownTimeout is the maximum time to wait for in-flight operations to complete when node is stopped.
+	ShutdownTimeout time.Duration
+	// LoopStallTimeout is the maximum time node loop can spend processing without making progress,
+	// before it's reported as stalled.
+	LoopStallTimeout time.Duration
+
+	// ProxyAppStartTimeout is the maximum time spent retrying to connect to the application.
+	// If it's zero, node makes single attempt to connect.
+	ProxyAppStartTimeout time.Duration
+
+	// IndexBlockEvents lists attributes of block-level (BeginBlock and EndBlock) events that blocks are indexed by.
+	// Attributes are identified by composite keys in the form "<event type>.<attribute key>".
+	// Index is kept in memory, unless node is created with node.WithStore.
+	IndexBlockEvents []string
+
+	// TxBatchSize is the maximum number of transactions gossiped in a single message. 1 disables batching.
+	// Batches are split further, so that messages don't exceed p2p.MaxPayloadSize.
+	TxBatchSize int
+	// TxBatchInterval is the maximum time transactions wait for batch to fill up before being gossiped.
+	TxBatchInterval time.Duration
+	// TxGossipRetryInterval is the time between retries of failed transaction gossip.
+	TxGossipRetryInterval time.Duration
+
+	// MinGasPrice is the minimum price of gas (fee divided by gas wanted) of transactions admitted to mempool.
+	// Zero accepts all transactions.
+	MinGasPrice float64
+	// FeeEventKey identifies CheckTx event attribute containing transaction fee, in the form
+	// "<event type>.<attribute key>". It's used only if MinGasPrice is set.
+	FeeEventKey string
+	// FeeDenom is the denomination of fee counted towards MinGasPrice. If it's empty, transactions have to pay fee in
+	// a single denomination.
+	FeeDenom string
+
+	// CanonicalTxOrder makes transactions reaped from mempool ordered by their hashes, instead of order of arrival.
+	// Aggregators with the same mempool contents produce identical block data (e.g. hot-standby aggregators).
+	// It can't be used with sequence based admission of transactions (see mempool.WithSequenceAdmission).
+	CanonicalTxOrder bool
+
+	// TxEvictionEvents enables publishing of EventTxEvicted on node event bus, when transaction accepted by the
+	// application is dropped from mempool without being included in a block.
+	TxEvictionEvents bool
+
+	// LogRejectedTxs makes transactions received from peers and rejected by the application in CheckTx logged at info
+	// level. By default they're logged at debug level; they're always counted in node RejectedTxs metric.
+	LogRejectedTxs bool
+
+	// CheckTxConcurrency is the number of workers executing CheckTx for transactions received from peers.
+	// Transactions from the same peer are checked in order of arrival; only retries of CheckTx (see CheckTxRetries)
+	// can be checked after later transactions.
+	CheckTxConcurrency int
+	// CheckTxRetries is the maximum number of retries of CheckTx that failed with a transient error (for example,
+	// because mempool was full or application connection failed). Negative value disables retries.
+	CheckTxRetries int
+	// CheckTxRetryInterval is the time before the first CheckTx retry; it's doubled for every subsequent retry.
+	CheckTxRetryInterval time.Duration
 }
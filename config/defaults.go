@@ -1,5 +1,65 @@
 package config
 
+import "time"
+
+// Node modes, controlling which node services are started.
+const (
+	// ModeFull runs all the services.
+	ModeFull = "full"
+	// ModeAggregator runs services required for block production.
+	ModeAggregator = "aggregator"
+	// ModeArchive only serves blocks; mempool is not running.
+	ModeArchive = "archive"
+)
+
+// P2P security transports.
+const (
+	// SecurityNoise secures connections with Noise protocol.
+	SecurityNoise = "noise"
+	// SecurityTLS secures connections with TLS 1.3.
+	SecurityTLS = "tls"
+)
+
+// P2P stream multiplexers.
+const (
+	MuxerYamux = "yamux"
+	MuxerMplex = "mplex"
+)
+
+// DefaultMuxers are stream multiplexers used when P2PConfig.Muxers is empty, in order of preference.
+var DefaultMuxers = []string{MuxerYamux, MuxerMplex}
+
 const (
+	MaxMonikerLength = 64
+
 	DefaultListenAddress = "/ip4/0.0.0.0/tcp/7676"
+	DefaultSecurity      = SecurityNoise
+
+	DefaultMaxInboundStreams = 256
+
+	DefaultPingInterval = 30 * time.Second
+	DefaultPingTimeout  = 10 * time.Second
+
+	DefaultGossipTTL      = 8
+	DefaultPublishTimeout = 5 * time.Second
+
+	DefaultDAStartTimeout = 30 * time.Second
+	DefaultDAStopTimeout  = 10 * time.Second
+
+	DefaultDAConfirmationDepth = 1
+	DefaultDAPollInterval      = 1 * time.Second
+	DefaultDAVerifyTimeout     = 10 * time.Minute
+
+	DefaultShutdownTimeout  = 10 * time.Second
+	DefaultLoopStallTimeout = 1 * time.Minute
+
+	DefaultFeeEventKey = "tx.fee"
+
+	DefaultCheckTxConcurrency   = 1
+	DefaultCheckTxRetries       = 3
+	DefaultCheckTxRetryInterval = 100 * time.Millisecond
+
+	DefaultTxBatchSize           = 1
+	DefaultTxBatchInterval       = 10 * time.Millisecond
+	DefaultTxGossipRetryInterval = 100 * time.Millisecond
 )
@@ -1,6 +1,44 @@
 package config
 
+import "time"
+
 type P2PConfig struct {
 	ListenAddress string // Address to listen for incoming connections
 	Seeds         string // Comma separated list of seed nodes to connect to
+
+	// Security is the transport security protocol used for all connections, SecurityNoise or SecurityTLS.
+	// Peers have to use the same protocol to connect. If it's empty, DefaultSecurity is used.
+	Security string
+	// Muxers are stream multiplexers (MuxerYamux, MuxerMplex) offered to peers, in order of preference.
+	// If it's empty, DefaultMuxers are used.
+	Muxers []string
+
+	// MaxInboundStreams is the maximum number of concurrently handled inbound streams per protocol, from all peers.
+	// Excess streams are reset. Gossipsub and DHT, which keep a long-lived stream per peer, are not limited. This limit
+	// is enforced in addition to per-connection stream limits of libp2p stream multiplexers, which bound streams opened
+	// by a single peer, but not the total.
+	MaxInboundStreams int
+
+	// PingInterval is the interval of keep-alive pings sent to connected peers.
+	PingInterval time.Duration
+	// PingTimeout is the maximum time to wait for ping response. Peers that don't respond are disconnected.
+	PingTimeout time.Duration
+
+	// TxLanes are names of additional transaction gossip lanes. Each lane uses separate pubsub topic.
+	// Transactions are assigned to lanes by p2p.TxRouter; by default all transactions use single, unnamed lane.
+	TxLanes []string
+
+	// ConfirmGossip makes gossiping a transaction fail with p2p.ErrNoPeers when no peer subscribes to its lane,
+	// so that it can be retried. Messages are flood-published to all subscribed peers. By default, gossip is
+	// fire-and-forget and transactions published when there are no peers are silently dropped.
+	ConfirmGossip bool
+
+	// PublishTimeout is the maximum time to wait for pubsub to accept a gossiped message. Publishing that takes
+	// longer fails with p2p.ErrPublishTimeout, so that callers aren't blocked by pubsub backpressure.
+	PublishTimeout time.Duration
+
+	// GossipTTL is the maximum number of hops transaction published by this node can travel. Nodes don't forward
+	// received gossip messages as-is; transaction is relayed only by re-publishing it with decremented TTL, and
+	// transactions received with TTL 1 are not relayed at all.
+	GossipTTL uint8
 }
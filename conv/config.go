@@ -14,6 +14,7 @@ func GetNodeConfig(cfg *tmcfg.Config) config.NodeConfig {
 	nodeConf := config.NodeConfig{}
 
 	if cfg != nil {
+		nodeConf.Moniker = cfg.Moniker
 		if cfg.P2P != nil {
 			nodeConf.P2P.ListenAddress = cfg.P2P.ListenAddress
 			nodeConf.P2P.Seeds = cfg.P2P.Seeds
@@ -18,6 +18,7 @@ func TestGetNodeConfig(t *testing.T) {
 		expected config.NodeConfig
 	}{
 		{"empty", nil, config.NodeConfig{}},
+		{"Moniker", &tmcfg.Config{BaseConfig: tmcfg.BaseConfig{Moniker: "node1"}}, config.NodeConfig{Moniker: "node1"}},
 		{"Seeds", &tmcfg.Config{P2P: &tmcfg.P2PConfig{Seeds: "seeds"}}, config.NodeConfig{P2P: config.P2PConfig{Seeds: "seeds"}}},
 		{"ListenAddress", &tmcfg.Config{P2P: &tmcfg.P2PConfig{ListenAddress: "127.0.0.1:7676"}}, config.NodeConfig{P2P: config.P2PConfig{ListenAddress: "127.0.0.1:7676"}}},
 	}
@@ -4,4 +4,7 @@ import "errors"
 
 var (
 	ErrInvalidAddress = errors.New("invalid address format, expected [protocol://][<NODE_ID>@]<IPv4>:<PORT>")
+
+	ErrAppStateConflict = errors.New("app state defined both in genesis document and in separate file")
+	ErrInvalidAppState  = errors.New("app state is not valid JSON")
 )
@@ -0,0 +1,60 @@
+package conv
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	tmjson "github.com/lazyledger/lazyledger-core/libs/json"
+	"github.com/lazyledger/lazyledger-core/types"
+	"github.com/minio/sha256-simd"
+)
+
+// GetGenesisDoc reads genesis document from genesisFile.
+//
+// If appStateFile is not empty, application state is read from this file instead of genesis document itself.
+// This allows keeping large initial application states outside of genesis file.
+func GetGenesisDoc(genesisFile, appStateFile string) (*types.GenesisDoc, error) {
+	genDoc, err := types.GenesisDocFromFile(genesisFile)
+	if err != nil {
+		return nil, err
+	}
+	if appStateFile == "" {
+		return genDoc, nil
+	}
+
+	if len(genDoc.AppState) > 0 {
+		return nil, ErrAppStateConflict
+	}
+	appState, err := ioutil.ReadFile(appStateFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read app state file: %w", err)
+	}
+	if !json.Valid(appState) {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidAppState, appStateFile)
+	}
+	genDoc.AppState = appState
+
+	return genDoc, nil
+}
+
+// GenesisHash returns deterministic hash of genesis document.
+//
+// Genesis document is serialized with Tendermint JSON encoding (fields in declaration order).
+// App state is compacted before hashing, so formatting of the genesis file doesn't change the hash.
+func GenesisHash(genDoc *types.GenesisDoc) ([32]byte, error) {
+	doc := *genDoc
+	if len(doc.AppState) > 0 {
+		var buf bytes.Buffer
+		if err := json.Compact(&buf, doc.AppState); err != nil {
+			return [32]byte{}, fmt.Errorf("%w: %v", ErrInvalidAppState, err)
+		}
+		doc.AppState = buf.Bytes()
+	}
+	data, err := tmjson.Marshal(&doc)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	return sha256.Sum256(data), nil
+}
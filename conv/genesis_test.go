@@ -0,0 +1,99 @@
+package conv
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lazyledger/lazyledger-core/types"
+)
+
+func TestGetGenesisDoc(t *testing.T) {
+	dir, err := ioutil.TempDir("", "genesis")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	writeFile := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, ioutil.WriteFile(path, []byte(content), 0600))
+		return path
+	}
+
+	genesis := writeFile("genesis.json", `{"chain_id": "test-chain"}`)
+	genesisWithState := writeFile("genesis_state.json", `{"chain_id": "test-chain", "app_state": {"inline": true}}`)
+	appState := writeFile("app_state.json", `{"accounts": [{"address": "addr1", "balance": 100}]}`)
+	invalidAppState := writeFile("invalid.json", `{"accounts": [`)
+
+	cases := []struct {
+		name             string
+		genesisFile      string
+		appStateFile     string
+		expectedAppState string
+		expectedErr      error
+	}{
+		{"no app state", genesis, "", "", nil},
+		{"inline app state", genesisWithState, "", `{"inline": true}`, nil},
+		{"external app state", genesis, appState, `{"accounts": [{"address": "addr1", "balance": 100}]}`, nil},
+		{"invalid external app state", genesis, invalidAppState, "", ErrInvalidAppState},
+		{"conflicting app state", genesisWithState, appState, "", ErrAppStateConflict},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			genDoc, err := GetGenesisDoc(c.genesisFile, c.appStateFile)
+			if c.expectedErr != nil {
+				assert.ErrorIs(err, c.expectedErr)
+				assert.Nil(genDoc)
+				return
+			}
+			require.NoError(err)
+			assert.Equal("test-chain", genDoc.ChainID)
+			if c.expectedAppState == "" {
+				assert.Empty(genDoc.AppState)
+			} else {
+				assert.Equal(json.RawMessage(c.expectedAppState), genDoc.AppState)
+			}
+		})
+	}
+
+	_, err = GetGenesisDoc(genesis, filepath.Join(dir, "missing.json"))
+	assert.Error(t, err)
+}
+
+func TestGenesisHash(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	genTime := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	doc := &types.GenesisDoc{ChainID: "test-chain", GenesisTime: genTime, AppState: json.RawMessage(`{"a": 1}`)}
+
+	hash, err := GenesisHash(doc)
+	require.NoError(err)
+
+	// formatting of app state doesn't matter
+	same, err := GenesisHash(&types.GenesisDoc{ChainID: "test-chain", GenesisTime: genTime, AppState: json.RawMessage("{\n  \"a\":1\n}")})
+	require.NoError(err)
+	assert.Equal(hash, same)
+
+	for _, other := range []*types.GenesisDoc{
+		{ChainID: "other-chain", GenesisTime: genTime, AppState: json.RawMessage(`{"a": 1}`)},
+		{ChainID: "test-chain", GenesisTime: genTime.Add(time.Second), AppState: json.RawMessage(`{"a": 1}`)},
+		{ChainID: "test-chain", GenesisTime: genTime, AppState: json.RawMessage(`{"a": 2}`)},
+	} {
+		otherHash, err := GenesisHash(other)
+		require.NoError(err)
+		assert.NotEqual(hash, otherHash)
+	}
+
+	_, err = GenesisHash(&types.GenesisDoc{AppState: json.RawMessage(`{`)})
+	assert.ErrorIs(err, ErrInvalidAppState)
+}
@@ -0,0 +1,72 @@
+package da
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/lazyledger/optimint/log"
+	"github.com/lazyledger/optimint/types"
+)
+
+// AuditEntry is a record of a single block submission attempt, written by AuditingClient.
+type AuditEntry struct {
+	Time      time.Time `json:"time"`
+	Height    uint64    `json:"height"`
+	Namespace string    `json:"namespace"`
+	Status    string    `json:"status"`
+	Message   string    `json:"message,omitempty"`
+	// DAHeight is the DA layer height at which block was included; it's zero if submission failed.
+	DAHeight uint64 `json:"da_height"`
+}
+
+// AuditingClient is a DA layer client that records every block submission attempt in an append-only audit log.
+//
+// Entries are written as JSON objects, one per line. Audit log is independent of the block store, so it can be used
+// to reconcile data included in DA layer with blocks that node submitted. Failure to write an entry doesn't affect
+// submission; it's only logged.
+type AuditingClient struct {
+	DataAvailabilityLayerClient
+
+	mtx    sync.Mutex
+	w      io.Writer
+	logger log.Logger
+}
+
+var _ DataAvailabilityLayerClient = &AuditingClient{}
+
+// NewAuditingClient returns DA layer client writing audit entries of blocks submitted with dalc to w.
+func NewAuditingClient(dalc DataAvailabilityLayerClient, w io.Writer, logger log.Logger) *AuditingClient {
+	return &AuditingClient{DataAvailabilityLayerClient: dalc, w: w, logger: logger}
+}
+
+// SubmitBlock submits block to the DA layer, and writes the result to the audit log.
+func (a *AuditingClient) SubmitBlock(block *types.Block) ResultSubmitBlock {
+	res := a.DataAvailabilityLayerClient.SubmitBlock(block)
+	entry := AuditEntry{
+		Time:      time.Now().UTC(),
+		Height:    block.Header.Height,
+		Namespace: hex.EncodeToString(block.Header.NamespaceID[:]),
+		Status:    res.Code.String(),
+		Message:   res.Message,
+		DAHeight:  res.DAHeight,
+	}
+	if err := a.write(entry); err != nil {
+		a.logger.Error("failed to write DA audit log entry", "height", entry.Height, "error", err)
+	}
+	return res
+}
+
+func (a *AuditingClient) write(entry AuditEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+	_, err = a.w.Write(append(data, '\n'))
+	return err
+}
@@ -0,0 +1,65 @@
+package da_test
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/lazyledger/lazyledger-core/libs/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lazyledger/optimint/da"
+	"github.com/lazyledger/optimint/da/mock"
+	"github.com/lazyledger/optimint/types"
+)
+
+func TestAuditingClient(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	dalc := &mock.MockDataAvailabilityLayerClient{}
+	require.NoError(dalc.Init(nil, log.TestingLogger()))
+	dalc.AdvanceHeight()
+	var buf bytes.Buffer
+	auditing := da.NewAuditingClient(dalc, &buf, log.TestingLogger())
+
+	block1 := &types.Block{Header: types.Header{Height: 1, NamespaceID: [8]byte{1, 2, 3, 4, 5, 6, 7, 8}}}
+	block2 := &types.Block{Header: types.Header{Height: 2, NamespaceID: [8]byte{1, 2, 3, 4, 5, 6, 7, 8}}}
+	require.Equal(da.StatusSuccess, auditing.SubmitBlock(block1).Code)
+	dalc.AdvanceHeight()
+	require.Equal(da.StatusSuccess, auditing.SubmitBlock(block2).Code)
+
+	// unregistered payload format makes submission fail
+	dalc.PayloadFormat = 0xff
+	require.Equal(da.StatusError, auditing.SubmitBlock(block2).Code)
+
+	var entries []da.AuditEntry
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		var entry da.AuditEntry
+		require.NoError(json.Unmarshal(scanner.Bytes(), &entry))
+		entries = append(entries, entry)
+	}
+	require.NoError(scanner.Err())
+	require.Len(entries, 3)
+
+	expected := []struct {
+		height   uint64
+		status   string
+		daHeight uint64
+	}{
+		{1, "success", 1},
+		{2, "success", 2},
+		{2, "error", 0},
+	}
+	for i, e := range expected {
+		assert.Equal(e.height, entries[i].Height)
+		assert.Equal("0102030405060708", entries[i].Namespace)
+		assert.Equal(e.status, entries[i].Status)
+		assert.Equal(e.daHeight, entries[i].DAHeight)
+		assert.False(entries[i].Time.IsZero())
+	}
+	assert.Contains(entries[2].Message, da.ErrUnknownPayloadFormat.Error())
+}
@@ -1,6 +1,8 @@
 package da
 
 import (
+	"context"
+
 	"github.com/lazyledger/optimint/log"
 	"github.com/lazyledger/optimint/types"
 )
@@ -17,25 +19,74 @@ const (
 	StatusError
 )
 
+// String returns human-readable name of the status code.
+func (c StatusCode) String() string {
+	switch c {
+	case StatusSuccess:
+		return "success"
+	case StatusTimeout:
+		return "timeout"
+	case StatusError:
+		return "error"
+	default:
+		return "unknown"
+	}
+}
+
 type ResultSubmitBlock struct {
 	// Code is to determine if the action succeeded.
 	Code StatusCode
 	// Message may contain DA layer specific information (like detailed error message)
 	Message string
+	// DAHeight is the DA layer height at which block was included.
+	DAHeight uint64
 	// Not sure if this needs to be bubbled up to other
 	// parts of Optimint.
 	// Hash hash.Hash
 }
 
+// ResultRetrieveBlocks contains blocks retrieved from single DA layer height and namespace.
+type ResultRetrieveBlocks struct {
+	// Code is to determine if the action succeeded.
+	Code StatusCode
+	// Message may contain DA layer specific information (like detailed error message)
+	Message string
+	// Blocks are all the blocks included at given DA layer height and namespace, in chain order (ascending
+	// Header.Height).
+	Blocks []*types.Block
+}
+
+// ResultCheckConfirmations contains number of DA layer confirmations of submitted block.
+type ResultCheckConfirmations struct {
+	// Code is to determine if the action succeeded.
+	Code StatusCode
+	// Message may contain DA layer specific information (like detailed error message)
+	Message string
+	// Confirmations is the number of DA layer blocks on top of (and including) the DA block containing submitted block.
+	// Zero means that block is not (or no longer, for example after DA layer reorg) included in DA layer.
+	Confirmations uint64
+}
+
 type DataAvailabilityLayerClient interface {
 	// Init is called once to allow DA client to read configuration and initialize resources.
 	Init(config []byte, logger log.Logger) error
 
-	Start() error
-	Stop() error
+	// Start is called once, before the client is used. Client should stop trying to start when ctx is done.
+	Start(ctx context.Context) error
+
+	// Stop is called once, when the client is no longer used. Client should stop trying to stop when ctx is done.
+	Stop(ctx context.Context) error
 
-	// SubmitBlock submits the passed in block to the DA layer.
+	// SubmitBlock submits the passed in block to the DA layer, in namespace from block header (Header.NamespaceID).
 	// This should create a transaction which (potentially)
 	// triggers a state transition in the DA layer.
 	SubmitBlock(block *types.Block) ResultSubmitBlock
+
+	// RetrieveBlocks returns all blocks included at given DA layer height in given namespace.
+	// Multiple blocks can be included at single DA layer height (for example, when they're submitted in batches).
+	// DA layer heights that are not available yet are reported with non-success code.
+	RetrieveBlocks(daHeight uint64, namespaceID [8]byte) ResultRetrieveBlocks
+
+	// CheckConfirmations returns the number of DA layer confirmations of previously submitted block.
+	CheckConfirmations(block *types.Block) ResultCheckConfirmations
 }
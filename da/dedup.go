@@ -0,0 +1,91 @@
+package da
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/lazyledger/optimint/log"
+	"github.com/lazyledger/optimint/store"
+	"github.com/lazyledger/optimint/types"
+)
+
+// submittedPrefix prefixes keys mapping hashes of submitted blocks to DA heights of their inclusion.
+//
+// Prefixes 1-8 are used by store package, so the same KVStore can be shared with block store.
+var submittedPrefix = [1]byte{9}
+
+// DeduplicatingClient is a DA layer client that doesn't submit blocks that were already successfully submitted.
+//
+// DA height of every successful submission is persisted in a key-value store. If the same block is submitted again
+// (for example, after node restart) and DA layer still confirms its inclusion, SubmitBlock returns success with
+// the recorded DA height, without submitting the block. Blocks that are no longer included (or whose inclusion
+// can't be checked) are submitted again.
+type DeduplicatingClient struct {
+	DataAvailabilityLayerClient
+
+	kv     store.KVStore
+	logger log.Logger
+}
+
+var _ DataAvailabilityLayerClient = &DeduplicatingClient{}
+
+// NewDeduplicatingClient returns DA layer client deduplicating blocks submitted with dalc, using kv to persist
+// DA heights of submitted blocks.
+func NewDeduplicatingClient(dalc DataAvailabilityLayerClient, kv store.KVStore, logger log.Logger) *DeduplicatingClient {
+	return &DeduplicatingClient{DataAvailabilityLayerClient: dalc, kv: kv, logger: logger}
+}
+
+// SubmitBlock submits block to the DA layer, unless it was already included in the DA layer.
+func (d *DeduplicatingClient) SubmitBlock(block *types.Block) ResultSubmitBlock {
+	hash := block.Header.Hash()
+	daHeight, err := d.submittedHeight(hash)
+	if err != nil {
+		d.logger.Error("failed to load DA height of submitted block", "height", block.Header.Height, "error", err)
+	}
+	if daHeight > 0 {
+		res := d.CheckConfirmations(block)
+		if res.Code == StatusSuccess && res.Confirmations > 0 {
+			d.logger.Debug("skipping already submitted block", "height", block.Header.Height, "daHeight", daHeight)
+			return ResultSubmitBlock{
+				Code:     StatusSuccess,
+				Message:  fmt.Sprintf("already submitted at DA height %d", daHeight),
+				DAHeight: daHeight,
+			}
+		}
+	}
+
+	res := d.DataAvailabilityLayerClient.SubmitBlock(block)
+	if res.Code == StatusSuccess {
+		if err := d.kv.Set(submittedKey(hash), encodeHeight(res.DAHeight)); err != nil {
+			d.logger.Error("failed to save DA height of submitted block", "height", block.Header.Height, "error", err)
+		}
+	}
+	return res
+}
+
+// submittedHeight returns DA height at which block with given hash was submitted, or 0 if it wasn't submitted.
+func (d *DeduplicatingClient) submittedHeight(hash [32]byte) (uint64, error) {
+	value, err := d.kv.Get(submittedKey(hash))
+	if errors.Is(err, store.ErrKeyNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	if len(value) != 8 {
+		return 0, fmt.Errorf("invalid DA height encoding: %d bytes", len(value))
+	}
+	return binary.LittleEndian.Uint64(value), nil
+}
+
+func submittedKey(hash [32]byte) []byte {
+	return append(submittedPrefix[:], hash[:]...)
+}
+
+// encodeHeight encodes height the same way as store package does.
+func encodeHeight(height uint64) []byte {
+	buf := make([]byte, 8)
+	binary.LittleEndian.PutUint64(buf, height)
+	return buf
+}
@@ -0,0 +1,78 @@
+package da_test
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/lazyledger/lazyledger-core/libs/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lazyledger/optimint/da"
+	"github.com/lazyledger/optimint/da/mock"
+	"github.com/lazyledger/optimint/store"
+	"github.com/lazyledger/optimint/types"
+)
+
+// countingDA is a mock DA layer client that counts submissions.
+type countingDA struct {
+	mock.MockDataAvailabilityLayerClient
+
+	submissions int
+}
+
+func (c *countingDA) SubmitBlock(block *types.Block) da.ResultSubmitBlock {
+	c.submissions++
+	return c.MockDataAvailabilityLayerClient.SubmitBlock(block)
+}
+
+func TestDeduplicatingClient(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "optimint-dedup")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	dalc := &countingDA{}
+	require.NoError(dalc.Init(nil, log.TestingLogger()))
+	dalc.AdvanceHeight()
+
+	block1 := &types.Block{Header: types.Header{Height: 1}}
+	block2 := &types.Block{Header: types.Header{Height: 2}}
+
+	kv, err := store.NewDiskKVStore(dir)
+	require.NoError(err)
+	dedup := da.NewDeduplicatingClient(dalc, kv, log.TestingLogger())
+	res := dedup.SubmitBlock(block1)
+	require.Equal(da.StatusSuccess, res.Code)
+	assert.Equal(uint64(1), res.DAHeight)
+	assert.Equal(1, dalc.submissions)
+	require.NoError(kv.(*store.BadgerKV).Close())
+
+	// restart: block submitted before restart is not submitted again
+	dalc.AdvanceHeight()
+	kv, err = store.NewDiskKVStore(dir)
+	require.NoError(err)
+	defer kv.(*store.BadgerKV).Close()
+	dedup = da.NewDeduplicatingClient(dalc, kv, log.TestingLogger())
+
+	res = dedup.SubmitBlock(block1)
+	require.Equal(da.StatusSuccess, res.Code)
+	assert.Equal(uint64(1), res.DAHeight)
+	assert.Equal(1, dalc.submissions)
+
+	res = dedup.SubmitBlock(block2)
+	require.Equal(da.StatusSuccess, res.Code)
+	assert.Equal(uint64(2), res.DAHeight)
+	assert.Equal(2, dalc.submissions)
+
+	// block that is no longer included in DA layer is submitted again
+	dalc.MockDataAvailabilityLayerClient = mock.MockDataAvailabilityLayerClient{}
+	require.NoError(dalc.Init(nil, log.TestingLogger()))
+	res = dedup.SubmitBlock(block1)
+	require.Equal(da.StatusSuccess, res.Code)
+	assert.Equal(uint64(0), res.DAHeight)
+	assert.Equal(3, dalc.submissions)
+}
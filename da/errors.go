@@ -0,0 +1,22 @@
+package da
+
+import "errors"
+
+var (
+	// ErrBlockNotIncluded is returned when submitted block is not (or no longer) included in DA layer.
+	ErrBlockNotIncluded = errors.New("block not included in DA layer")
+	// ErrCheckConfirmations is returned when DA layer client fails to check block confirmations.
+	ErrCheckConfirmations = errors.New("failed to check block confirmations")
+	// ErrRetrieveBlocks is returned when DA layer client fails to retrieve blocks.
+	ErrRetrieveBlocks = errors.New("failed to retrieve blocks")
+	// ErrBlockMismatch is returned when block retrieved from DA layer is different than submitted block.
+	ErrBlockMismatch = errors.New("retrieved block doesn't match submitted block")
+	// ErrUnknownPayloadFormat is returned when blob format is not registered.
+	ErrUnknownPayloadFormat = errors.New("unknown DA payload format")
+	// ErrDuplicatePayloadFormat is returned when payload format is registered more than once.
+	ErrDuplicatePayloadFormat = errors.New("DA payload format already registered")
+	// ErrClientStopped is returned when block is submitted with DA layer client that is stopping or stopped.
+	ErrClientStopped = errors.New("DA layer client stopped")
+	// ErrEmptyPayload is returned when decoding an empty blob.
+	ErrEmptyPayload = errors.New("empty DA payload")
+)
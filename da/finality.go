@@ -0,0 +1,84 @@
+package da
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/lazyledger/optimint/types"
+)
+
+// BlockStatus describes durability of block submitted to DA layer.
+type BlockStatus int
+
+const (
+	// BlockStatusUnknown means that block is not included in DA layer (yet or anymore).
+	BlockStatusUnknown BlockStatus = iota
+	// BlockStatusSubmitted means that block is included in DA layer, but doesn't have enough confirmations.
+	BlockStatusSubmitted
+	// BlockStatusFinal means that block has at least required number of DA layer confirmations.
+	BlockStatusFinal
+)
+
+func (s BlockStatus) String() string {
+	switch s {
+	case BlockStatusSubmitted:
+		return "submitted"
+	case BlockStatusFinal:
+		return "final"
+	default:
+		return "unknown"
+	}
+}
+
+// CheckBlockStatus returns status of block submitted to DA layer, given required confirmation depth.
+func CheckBlockStatus(dalc DataAvailabilityLayerClient, block *types.Block, depth uint64) (BlockStatus, error) {
+	res := dalc.CheckConfirmations(block)
+	if res.Code != StatusSuccess {
+		return BlockStatusUnknown, fmt.Errorf("%w: %s", ErrCheckConfirmations, res.Message)
+	}
+	switch {
+	case res.Confirmations == 0:
+		return BlockStatusUnknown, nil
+	case res.Confirmations < depth:
+		return BlockStatusSubmitted, nil
+	default:
+		return BlockStatusFinal, nil
+	}
+}
+
+// WaitForFinality polls DA layer every pollInterval, until block has at least depth confirmations.
+//
+// Block that is not included in DA layer yet is polled until ctx is done; ErrBlockNotIncluded is returned if it
+// doesn't appear by then. ErrBlockNotIncluded is returned immediately if block was included, but then disappeared
+// from DA layer, for example because DA submission was reorged away. Such block has to be submitted again.
+func WaitForFinality(ctx context.Context, dalc DataAvailabilityLayerClient, block *types.Block, depth uint64, pollInterval time.Duration) error {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	included := false
+	for {
+		status, err := CheckBlockStatus(dalc, block, depth)
+		if err != nil {
+			return err
+		}
+		switch status {
+		case BlockStatusFinal:
+			return nil
+		case BlockStatusSubmitted:
+			included = true
+		case BlockStatusUnknown:
+			if included {
+				return fmt.Errorf("%w: height %d", ErrBlockNotIncluded, block.Header.Height)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			if !included {
+				return fmt.Errorf("%w: height %d: %v", ErrBlockNotIncluded, block.Header.Height, ctx.Err())
+			}
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
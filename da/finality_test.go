@@ -0,0 +1,114 @@
+package da_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/lazyledger/lazyledger-core/libs/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lazyledger/optimint/da"
+	"github.com/lazyledger/optimint/da/mock"
+	"github.com/lazyledger/optimint/types"
+)
+
+// pollingDA is a mock DA layer client producing new DA layer block on every confirmations check.
+type pollingDA struct {
+	mock.MockDataAvailabilityLayerClient
+
+	polls      int
+	reorgAt    int
+	failures   int
+	includedAt int
+}
+
+func (p *pollingDA) CheckConfirmations(block *types.Block) da.ResultCheckConfirmations {
+	p.polls++
+	if p.failures > 0 {
+		p.failures--
+		return da.ResultCheckConfirmations{Code: da.StatusError, Message: "failure"}
+	}
+	if p.polls == p.reorgAt || p.polls < p.includedAt {
+		return da.ResultCheckConfirmations{Code: da.StatusSuccess}
+	}
+	res := p.MockDataAvailabilityLayerClient.CheckConfirmations(block)
+	p.AdvanceHeight()
+	return res
+}
+
+func TestWaitForFinality(t *testing.T) {
+	cases := []struct {
+		name          string
+		depth         uint64
+		reorgAt       int
+		failures      int
+		includedAt    int
+		expectedPolls int
+		expectedErr   error
+	}{
+		{"final on submission", 1, 0, 0, 0, 1, nil},
+		{"final after 5 polls", 5, 0, 0, 0, 5, nil},
+		{"included after 3 polls", 1, 0, 0, 3, 3, nil},
+		{"reorged", 5, 3, 0, 0, 3, da.ErrBlockNotIncluded},
+		{"DA error", 5, 0, 1, 0, 1, da.ErrCheckConfirmations},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			dalc := &pollingDA{reorgAt: c.reorgAt, failures: c.failures, includedAt: c.includedAt}
+			require.NoError(dalc.Init(nil, log.TestingLogger()))
+
+			block := &types.Block{Header: types.Header{Height: 1}}
+			require.Equal(da.StatusSuccess, dalc.SubmitBlock(block).Code)
+
+			err := da.WaitForFinality(context.Background(), dalc, block, c.depth, time.Millisecond)
+			if c.expectedErr != nil {
+				assert.True(errors.Is(err, c.expectedErr), err)
+			} else {
+				assert.NoError(err)
+			}
+			assert.Equal(c.expectedPolls, dalc.polls)
+		})
+	}
+}
+
+func TestBlockStatus(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	dalc := &mock.MockDataAvailabilityLayerClient{}
+	require.NoError(dalc.Init(nil, log.TestingLogger()))
+	block := &types.Block{Header: types.Header{Height: 1}}
+	require.Equal(da.StatusSuccess, dalc.SubmitBlock(block).Code)
+
+	status, err := da.CheckBlockStatus(dalc, block, 2)
+	assert.NoError(err)
+	assert.Equal(da.BlockStatusSubmitted, status)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err = da.WaitForFinality(ctx, dalc, block, 2, time.Millisecond)
+	assert.True(errors.Is(err, context.DeadlineExceeded))
+
+	dalc.AdvanceHeight()
+	status, err = da.CheckBlockStatus(dalc, block, 2)
+	assert.NoError(err)
+	assert.Equal(da.BlockStatusFinal, status)
+
+	status, err = da.CheckBlockStatus(dalc, &types.Block{Header: types.Header{Height: 2}}, 2)
+	assert.NoError(err)
+	assert.Equal(da.BlockStatusUnknown, status)
+
+	// block that never appears in DA layer is polled until ctx is done
+	ctx, cancel = context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err = da.WaitForFinality(ctx, dalc, &types.Block{Header: types.Header{Height: 2}}, 2, time.Millisecond)
+	assert.True(errors.Is(err, da.ErrBlockNotIncluded), err)
+	assert.Error(ctx.Err())
+}
@@ -1,29 +1,68 @@
 package mock
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+
 	"github.com/lazyledger/optimint/da"
 	"github.com/lazyledger/optimint/log"
 	"github.com/lazyledger/optimint/types"
 )
 
+// MockDataAvailabilityLayerClient is a simple, in-memory DA layer client used in tests.
+//
+// Submitted blocks are included at current DA layer height, until AdvanceHeight is called. Blocks are stored as
+// blobs encoded with PayloadFormat, like in real DA layer, in namespace from block header.
 type MockDataAvailabilityLayerClient struct {
 	logger log.Logger
 
 	Blocks []*types.Block
+	// PayloadFormat is the format of submitted blobs. If it's zero, da.DefaultPayloadFormat is used.
+	// It can be set with Config, passed to Init.
+	PayloadFormat da.PayloadFormat
+
+	mtx      sync.Mutex
+	daHeight uint64
+	byHeight map[uint64]map[[8]byte][][]byte
+	included map[[32]byte]uint64
+}
+
+// Config is the JSON encoded configuration of the mock DA layer client (config.NodeConfig.DAConfig).
+type Config struct {
+	// PayloadFormat is the format of submitted blobs; it has to be registered (see da.RegisterPayloadFormat).
+	PayloadFormat da.PayloadFormat `json:"payload_format"`
 }
 
 // Init is called once to allow DA client to read configuration and initialize resources.
+//
+// Empty config leaves PayloadFormat unchanged.
 func (m *MockDataAvailabilityLayerClient) Init(config []byte, logger log.Logger) error {
 	m.logger = logger
+	if len(config) == 0 {
+		return nil
+	}
+	var conf Config
+	if err := json.Unmarshal(config, &conf); err != nil {
+		return fmt.Errorf("invalid mock DA layer config: %w", err)
+	}
+	if conf.PayloadFormat != 0 {
+		if err := da.ValidatePayloadFormat(conf.PayloadFormat); err != nil {
+			return err
+		}
+		m.PayloadFormat = conf.PayloadFormat
+	}
 	return nil
 }
 
-func (m *MockDataAvailabilityLayerClient) Start() error {
+func (m *MockDataAvailabilityLayerClient) Start(ctx context.Context) error {
 	m.logger.Debug("Mock Data Availability Layer Client starting")
 	return nil
 }
 
-func (m *MockDataAvailabilityLayerClient) Stop() error {
+func (m *MockDataAvailabilityLayerClient) Stop(ctx context.Context) error {
 	m.logger.Debug("Mock Data Availability Layer Client stopped")
 	return nil
 }
@@ -32,10 +71,89 @@ func (m *MockDataAvailabilityLayerClient) Stop() error {
 // This should create a transaction which (potentially)
 // triggers a state transition in the DA layer.
 func (m *MockDataAvailabilityLayerClient) SubmitBlock(block *types.Block) da.ResultSubmitBlock {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	format := m.PayloadFormat
+	if format == 0 {
+		format = da.DefaultPayloadFormat
+	}
+	blob, err := da.EncodeBlock(format, block)
+	if err != nil {
+		return da.ResultSubmitBlock{Code: da.StatusError, Message: err.Error()}
+	}
+
 	m.Blocks = append(m.Blocks, block)
+	if m.byHeight == nil {
+		m.byHeight = make(map[uint64]map[[8]byte][][]byte)
+	}
+	if m.byHeight[m.daHeight] == nil {
+		m.byHeight[m.daHeight] = make(map[[8]byte][][]byte)
+	}
+	nID := block.Header.NamespaceID
+	m.byHeight[m.daHeight][nID] = append(m.byHeight[m.daHeight][nID], blob)
+	if m.included == nil {
+		m.included = make(map[[32]byte]uint64)
+	}
+	m.included[block.Header.Hash()] = m.daHeight
 
 	return da.ResultSubmitBlock{
+		Code:     da.StatusSuccess,
+		Message:  "OK",
+		DAHeight: m.daHeight,
+	}
+}
+
+// RetrieveBlocks returns all blocks submitted at given DA layer height in given namespace, in chain order.
+// Heights above current DA layer height are reported with StatusError.
+func (m *MockDataAvailabilityLayerClient) RetrieveBlocks(daHeight uint64, namespaceID [8]byte) da.ResultRetrieveBlocks {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	if daHeight > m.daHeight {
+		return da.ResultRetrieveBlocks{
+			Code:    da.StatusError,
+			Message: fmt.Sprintf("DA height %d not reached (current: %d)", daHeight, m.daHeight),
+		}
+	}
+
+	blobs := m.byHeight[daHeight][namespaceID]
+	blocks := make([]*types.Block, len(blobs))
+	for i, blob := range blobs {
+		block, err := da.DecodeBlock(blob)
+		if err != nil {
+			return da.ResultRetrieveBlocks{Code: da.StatusError, Message: err.Error()}
+		}
+		blocks[i] = block
+	}
+	sort.SliceStable(blocks, func(i, j int) bool {
+		return blocks[i].Header.Height < blocks[j].Header.Height
+	})
+
+	return da.ResultRetrieveBlocks{
 		Code:    da.StatusSuccess,
 		Message: "OK",
+		Blocks:  blocks,
 	}
 }
+
+// CheckConfirmations returns the number of DA layer heights since block was submitted, including the height of submission.
+func (m *MockDataAvailabilityLayerClient) CheckConfirmations(block *types.Block) da.ResultCheckConfirmations {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	res := da.ResultCheckConfirmations{Code: da.StatusSuccess, Message: "OK"}
+	if daHeight, ok := m.included[block.Header.Hash()]; ok {
+		res.Confirmations = m.daHeight - daHeight + 1
+	}
+	return res
+}
+
+// AdvanceHeight simulates new DA layer block. Blocks submitted later are included at next DA layer height.
+func (m *MockDataAvailabilityLayerClient) AdvanceHeight() uint64 {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	m.daHeight++
+	return m.daHeight
+}
@@ -0,0 +1,164 @@
+package mock
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/lazyledger/lazyledger-core/libs/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lazyledger/optimint/da"
+	"github.com/lazyledger/optimint/store"
+	"github.com/lazyledger/optimint/types"
+)
+
+func TestRetrieveMultipleBlocks(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	dalc := &MockDataAvailabilityLayerClient{}
+	require.NoError(dalc.Init(nil, log.TestingLogger()))
+
+	blocks := make([]*types.Block, 4)
+	for i := range blocks {
+		blocks[i] = &types.Block{Header: types.Header{Height: uint64(i + 1)}}
+		if i > 0 {
+			blocks[i].Header.LastHeaderHash = blocks[i-1].Header.Hash()
+		}
+	}
+
+	// three blocks in single DA height, submitted out of order
+	for _, b := range []*types.Block{blocks[1], blocks[0], blocks[2]} {
+		assert.Equal(da.StatusSuccess, dalc.SubmitBlock(b).Code)
+	}
+	daHeight := dalc.AdvanceHeight()
+	assert.Equal(da.StatusSuccess, dalc.SubmitBlock(blocks[3]).Code)
+
+	resp := dalc.RetrieveBlocks(daHeight-1, [8]byte{})
+	require.Equal(da.StatusSuccess, resp.Code)
+	require.Len(resp.Blocks, 3)
+
+	bstore := store.NewBlockStore()
+	for i, b := range resp.Blocks {
+		assert.Equal(blocks[i], b)
+		if i > 0 {
+			assert.Equal(resp.Blocks[i-1].Header.Hash(), b.Header.LastHeaderHash)
+		}
+		require.NoError(bstore.SaveBlock(b))
+	}
+	assert.Equal(uint64(3), bstore.Height())
+
+	resp = dalc.RetrieveBlocks(daHeight, [8]byte{})
+	require.Equal(da.StatusSuccess, resp.Code)
+	assert.Equal([]*types.Block{blocks[3]}, resp.Blocks)
+
+	resp = dalc.RetrieveBlocks(daHeight+1, [8]byte{})
+	assert.Equal(da.StatusError, resp.Code)
+	assert.Empty(resp.Blocks)
+}
+
+func TestRetrieveNamespace(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	dalc := &MockDataAvailabilityLayerClient{}
+	require.NoError(dalc.Init(nil, log.TestingLogger()))
+
+	nID1, nID2 := [8]byte{1}, [8]byte{2}
+	block1 := &types.Block{Header: types.Header{Height: 1, NamespaceID: nID1}}
+	block2 := &types.Block{Header: types.Header{Height: 2, NamespaceID: nID2}}
+	assert.Equal(da.StatusSuccess, dalc.SubmitBlock(block1).Code)
+	assert.Equal(da.StatusSuccess, dalc.SubmitBlock(block2).Code)
+
+	// blocks are retrieved only from requested namespace
+	resp := dalc.RetrieveBlocks(0, nID1)
+	require.Equal(da.StatusSuccess, resp.Code)
+	assert.Equal([]*types.Block{block1}, resp.Blocks)
+	resp = dalc.RetrieveBlocks(0, nID2)
+	require.Equal(da.StatusSuccess, resp.Code)
+	assert.Equal([]*types.Block{block2}, resp.Blocks)
+	resp = dalc.RetrieveBlocks(0, [8]byte{3})
+	require.Equal(da.StatusSuccess, resp.Code)
+	assert.Empty(resp.Blocks)
+}
+
+// payloadFormatJSON simulates format introduced after PayloadFormatBinary.
+const payloadFormatJSON da.PayloadFormat = 2
+
+func init() {
+	if err := da.RegisterPayloadFormat(payloadFormatJSON, jsonCodec{}); err != nil {
+		panic(err)
+	}
+}
+
+func TestRetrieveMixedPayloadFormats(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	dalc := &MockDataAvailabilityLayerClient{}
+	require.NoError(dalc.Init(nil, log.TestingLogger()))
+
+	oldBlock := &types.Block{
+		Header: types.Header{Height: 1},
+		Data:   types.Data{Txs: types.Txs{types.Tx("tx1")}},
+	}
+	newBlock := &types.Block{
+		Header: types.Header{Height: 2, LastHeaderHash: oldBlock.Header.Hash()},
+		Data:   types.Data{Txs: types.Txs{types.Tx("tx2")}},
+	}
+
+	// format is changed between submissions to the same namespace, like during an upgrade
+	assert.Equal(da.StatusSuccess, dalc.SubmitBlock(oldBlock).Code)
+	dalc.PayloadFormat = payloadFormatJSON
+	assert.Equal(da.StatusSuccess, dalc.SubmitBlock(newBlock).Code)
+
+	blobs := dalc.byHeight[0][[8]byte{}]
+	require.Len(blobs, 2)
+	assert.Equal(byte(da.PayloadFormatBinary), blobs[0][0])
+	assert.Equal(byte(payloadFormatJSON), blobs[1][0])
+	assert.True(json.Valid(blobs[1][1:]))
+
+	resp := dalc.RetrieveBlocks(0, [8]byte{})
+	require.Equal(da.StatusSuccess, resp.Code)
+	assert.Equal([]*types.Block{oldBlock, newBlock}, resp.Blocks)
+
+	// blocks can't be submitted in unknown format
+	dalc.PayloadFormat = payloadFormatJSON + 1
+	assert.Equal(da.StatusError, dalc.SubmitBlock(newBlock).Code)
+}
+
+func TestPayloadFormatConfig(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	dalc := &MockDataAvailabilityLayerClient{}
+	require.NoError(dalc.Init([]byte(`{"payload_format": 2}`), log.TestingLogger()))
+	assert.Equal(payloadFormatJSON, dalc.PayloadFormat)
+
+	assert.Equal(da.StatusSuccess, dalc.SubmitBlock(&types.Block{Header: types.Header{Height: 1}}).Code)
+	blobs := dalc.byHeight[0][[8]byte{}]
+	require.Len(blobs, 1)
+	assert.Equal(byte(payloadFormatJSON), blobs[0][0])
+
+	assert.ErrorIs(dalc.Init([]byte(`{"payload_format": 3}`), log.TestingLogger()), da.ErrUnknownPayloadFormat)
+	assert.Error(dalc.Init([]byte(`payload_format`), log.TestingLogger()))
+}
+
+type jsonCodec struct{}
+
+func (jsonCodec) MarshalBlock(block *types.Block) ([]byte, error) {
+	return json.Marshal(block)
+}
+
+func (jsonCodec) UnmarshalBlock(data []byte, block *types.Block) error {
+	return json.Unmarshal(data, block)
+}
+
+func (jsonCodec) MarshalCommit(commit *types.Commit) ([]byte, error) {
+	return json.Marshal(commit)
+}
+
+func (jsonCodec) UnmarshalCommit(data []byte, commit *types.Commit) error {
+	return json.Unmarshal(data, commit)
+}
@@ -0,0 +1,92 @@
+package da
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/lazyledger/optimint/types"
+)
+
+// PayloadFormat identifies encoding of a block submitted to DA layer.
+//
+// Format is stored in the first byte of every blob, so blobs submitted with different formats (for example, before
+// and after an upgrade) can be retrieved from the same namespace.
+type PayloadFormat uint8
+
+const (
+	// PayloadFormatBinary encodes blocks with types.DefaultCodec.
+	PayloadFormatBinary PayloadFormat = 1
+
+	// DefaultPayloadFormat is the format used by DA layer clients, unless configured otherwise.
+	DefaultPayloadFormat = PayloadFormatBinary
+)
+
+var (
+	payloadMtx    sync.RWMutex
+	payloadCodecs = map[PayloadFormat]types.Codec{
+		PayloadFormatBinary: types.DefaultCodec,
+	}
+)
+
+// RegisterPayloadFormat adds codec used to encode and decode blobs of given format.
+//
+// Error is returned if format is 0 or already registered. RegisterPayloadFormat is safe for concurrent use.
+func RegisterPayloadFormat(format PayloadFormat, codec types.Codec) error {
+	payloadMtx.Lock()
+	defer payloadMtx.Unlock()
+
+	if format == 0 {
+		return fmt.Errorf("%w: %d", ErrUnknownPayloadFormat, format)
+	}
+	if _, ok := payloadCodecs[format]; ok {
+		return fmt.Errorf("%w: %d", ErrDuplicatePayloadFormat, format)
+	}
+	payloadCodecs[format] = codec
+	return nil
+}
+
+// ValidatePayloadFormat returns error if format is not registered, so it can't be used to encode blocks.
+func ValidatePayloadFormat(format PayloadFormat) error {
+	_, err := payloadCodec(format)
+	return err
+}
+
+// EncodeBlock encodes block into a blob of given format, prefixed with format byte.
+func EncodeBlock(format PayloadFormat, block *types.Block) ([]byte, error) {
+	codec, err := payloadCodec(format)
+	if err != nil {
+		return nil, err
+	}
+	data, err := codec.MarshalBlock(block)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte{byte(format)}, data...), nil
+}
+
+// DecodeBlock decodes blob created by EncodeBlock, using codec of format stored in the blob.
+func DecodeBlock(blob []byte) (*types.Block, error) {
+	if len(blob) == 0 {
+		return nil, ErrEmptyPayload
+	}
+	codec, err := payloadCodec(PayloadFormat(blob[0]))
+	if err != nil {
+		return nil, err
+	}
+	block := new(types.Block)
+	if err := codec.UnmarshalBlock(blob[1:], block); err != nil {
+		return nil, err
+	}
+	return block, nil
+}
+
+func payloadCodec(format PayloadFormat) (types.Codec, error) {
+	payloadMtx.RLock()
+	defer payloadMtx.RUnlock()
+
+	codec, ok := payloadCodecs[format]
+	if !ok {
+		return nil, fmt.Errorf("%w: %d", ErrUnknownPayloadFormat, format)
+	}
+	return codec, nil
+}
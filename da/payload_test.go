@@ -0,0 +1,47 @@
+package da
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lazyledger/optimint/types"
+)
+
+func TestPayloadRoundTrip(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	block := &types.Block{
+		Header: types.Header{Height: 1},
+		Data:   types.Data{Txs: types.Txs{types.Tx("tx1")}},
+	}
+	blob, err := EncodeBlock(DefaultPayloadFormat, block)
+	require.NoError(err)
+	assert.Equal(byte(DefaultPayloadFormat), blob[0])
+
+	decoded, err := DecodeBlock(blob)
+	require.NoError(err)
+	assert.Equal(block, decoded)
+}
+
+func TestPayloadErrors(t *testing.T) {
+	assert := assert.New(t)
+
+	block := &types.Block{Header: types.Header{Height: 1}}
+	_, err := EncodeBlock(0, block)
+	assert.ErrorIs(err, ErrUnknownPayloadFormat)
+	_, err = EncodeBlock(PayloadFormatBinary+1, block)
+	assert.ErrorIs(err, ErrUnknownPayloadFormat)
+
+	_, err = DecodeBlock(nil)
+	assert.ErrorIs(err, ErrEmptyPayload)
+	_, err = DecodeBlock([]byte{byte(PayloadFormatBinary + 1), 1, 2, 3})
+	assert.ErrorIs(err, ErrUnknownPayloadFormat)
+	_, err = DecodeBlock([]byte{byte(PayloadFormatBinary), 1, 2, 3})
+	assert.Error(err)
+
+	assert.ErrorIs(RegisterPayloadFormat(0, types.DefaultCodec), ErrUnknownPayloadFormat)
+	assert.ErrorIs(RegisterPayloadFormat(PayloadFormatBinary, types.DefaultCodec), ErrDuplicatePayloadFormat)
+}
@@ -0,0 +1,72 @@
+package registry
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/lazyledger/optimint/da"
+	"github.com/lazyledger/optimint/da/mock"
+)
+
+// ErrDuplicateName is returned when DA layer client is registered with a name that is already in use.
+var ErrDuplicateName = errors.New("DA layer client already registered")
+
+// ClientConstructor creates new, uninitialized instance of DA layer client.
+type ClientConstructor func() da.DataAvailabilityLayerClient
+
+var (
+	mtx     sync.RWMutex
+	clients = map[string]ClientConstructor{}
+)
+
+func init() {
+	MustRegister("mock", func() da.DataAvailabilityLayerClient { return &mock.MockDataAvailabilityLayerClient{} })
+}
+
+// Register adds DA layer client constructor to the registry.
+//
+// Error is returned if name is already registered. Register is safe for concurrent use.
+func Register(name string, constructor ClientConstructor) error {
+	mtx.Lock()
+	defer mtx.Unlock()
+
+	if _, ok := clients[name]; ok {
+		return fmt.Errorf("%w: %q", ErrDuplicateName, name)
+	}
+	clients[name] = constructor
+	return nil
+}
+
+// MustRegister is like Register, but panics on error. It's intended to be used in init functions.
+func MustRegister(name string, constructor ClientConstructor) {
+	if err := Register(name, constructor); err != nil {
+		panic(err)
+	}
+}
+
+// GetClient returns new instance of DA layer client registered with given name, or nil if name is unknown.
+func GetClient(name string) da.DataAvailabilityLayerClient {
+	mtx.RLock()
+	defer mtx.RUnlock()
+
+	constructor, ok := clients[name]
+	if !ok {
+		return nil
+	}
+	return constructor()
+}
+
+// RegisteredClients returns sorted names of all registered DA layer clients.
+func RegisteredClients() []string {
+	mtx.RLock()
+	defer mtx.RUnlock()
+
+	names := make([]string, 0, len(clients))
+	for name := range clients {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
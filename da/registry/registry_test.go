@@ -0,0 +1,32 @@
+package registry
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/lazyledger/optimint/da"
+	"github.com/lazyledger/optimint/da/mock"
+)
+
+func TestRegistry(t *testing.T) {
+	assert := assert.New(t)
+
+	newMock := func() da.DataAvailabilityLayerClient { return &mock.MockDataAvailabilityLayerClient{} }
+
+	assert.Equal([]string{"mock"}, RegisteredClients())
+	assert.NotNil(GetClient("mock"))
+	assert.Nil(GetClient("nonexistent"))
+
+	err := Register("mock", newMock)
+	assert.ErrorIs(err, ErrDuplicateName)
+	assert.Panics(func() { MustRegister("mock", newMock) })
+
+	err = Register("custom", newMock)
+	assert.NoError(err)
+	assert.Equal([]string{"custom", "mock"}, RegisteredClients())
+	assert.NotNil(GetClient("custom"))
+
+	// every call should create new instance
+	assert.NotSame(GetClient("custom"), GetClient("custom"))
+}
@@ -0,0 +1,53 @@
+package da
+
+import (
+	"context"
+	"sync"
+
+	"github.com/lazyledger/optimint/types"
+)
+
+// SubmitBlocks submits blocks to the DA layer, running at most concurrency submissions in parallel.
+//
+// Results are returned in the order of blocks, regardless of the order in which submissions complete.
+// Blocks that were not submitted because ctx was done get StatusError result.
+func SubmitBlocks(ctx context.Context, dalc DataAvailabilityLayerClient, blocks []*types.Block, concurrency int) []ResultSubmitBlock {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	results := make([]ResultSubmitBlock, len(blocks))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i := range blocks {
+		if !acquire(ctx, sem) {
+			for j := i; j < len(blocks); j++ {
+				results[j] = ResultSubmitBlock{Code: StatusError, Message: ctx.Err().Error()}
+			}
+			break
+		}
+		wg.Add(1)
+		go func(i int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			results[i] = dalc.SubmitBlock(blocks[i])
+		}(i)
+	}
+	wg.Wait()
+	return results
+}
+
+// acquire takes a slot in sem, unless ctx is done.
+func acquire(ctx context.Context, sem chan struct{}) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	select {
+	case sem <- struct{}{}:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
@@ -0,0 +1,83 @@
+package da_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/lazyledger/lazyledger-core/libs/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lazyledger/optimint/da"
+	"github.com/lazyledger/optimint/da/mock"
+	"github.com/lazyledger/optimint/types"
+)
+
+// slowDA is a mock DA layer client with slow submissions, tracking maximum number of concurrent submissions.
+type slowDA struct {
+	mock.MockDataAvailabilityLayerClient
+
+	active    int32
+	maxActive int32
+}
+
+func (s *slowDA) SubmitBlock(block *types.Block) da.ResultSubmitBlock {
+	active := atomic.AddInt32(&s.active, 1)
+	defer atomic.AddInt32(&s.active, -1)
+	for {
+		max := atomic.LoadInt32(&s.maxActive)
+		if active <= max || atomic.CompareAndSwapInt32(&s.maxActive, max, active) {
+			break
+		}
+	}
+	// later blocks complete first
+	time.Sleep(time.Duration(20-block.Header.Height) * time.Millisecond)
+	if block.Header.Height%5 == 0 {
+		return da.ResultSubmitBlock{Code: da.StatusError, Message: "failure"}
+	}
+	return s.MockDataAvailabilityLayerClient.SubmitBlock(block)
+}
+
+func TestSubmitBlocks(t *testing.T) {
+	for _, concurrency := range []int{1, 4, 20} {
+		t.Run("", func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			dalc := &slowDA{}
+			require.NoError(dalc.Init(nil, log.TestingLogger()))
+
+			blocks := make([]*types.Block, 20)
+			for i := range blocks {
+				blocks[i] = &types.Block{Header: types.Header{Height: uint64(i + 1)}}
+			}
+
+			results := da.SubmitBlocks(context.Background(), dalc, blocks, concurrency)
+			require.Len(results, len(blocks))
+			for i, res := range results {
+				if blocks[i].Header.Height%5 == 0 {
+					assert.Equal(da.StatusError, res.Code)
+				} else {
+					assert.Equal(da.StatusSuccess, res.Code)
+				}
+			}
+			assert.Len(dalc.Blocks, 16)
+			assert.LessOrEqual(atomic.LoadInt32(&dalc.maxActive), int32(concurrency))
+		})
+	}
+}
+
+func TestSubmitBlocksCancelled(t *testing.T) {
+	assert := assert.New(t)
+
+	dalc := &slowDA{}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	results := da.SubmitBlocks(ctx, dalc, []*types.Block{{}, {}}, 1)
+	for _, res := range results {
+		assert.Equal(da.StatusError, res.Code)
+	}
+}
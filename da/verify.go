@@ -0,0 +1,148 @@
+package da
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/lazyledger/optimint/types"
+)
+
+// VerifyFunc is called with the result of verification of a submitted block; err is nil if verification succeeded.
+type VerifyFunc func(block *types.Block, err error)
+
+// VerifyingClient is a DA layer client that verifies every submitted block by retrieving it back from the DA layer.
+//
+// Verification doubles the cost of block submission, but detects DA layers that silently drop or corrupt data.
+// Block is verified in background, once it has required number of DA layer confirmations, so SubmitBlock is not
+// delayed and DA layers that don't serve blocks right after submission are supported. Block that doesn't reach
+// required confirmations within timeout fails verification with ErrBlockNotIncluded.
+type VerifyingClient struct {
+	DataAvailabilityLayerClient
+
+	depth        uint64
+	pollInterval time.Duration
+	timeout      time.Duration
+	onVerified   VerifyFunc
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	// wg tracks in-flight submissions and verifications
+	wg sync.WaitGroup
+	// stopped is set when Stop is called; mtx protects it, and orders wg.Add before wg.Wait
+	stopped bool
+	mtx     sync.Mutex
+}
+
+var _ DataAvailabilityLayerClient = &VerifyingClient{}
+
+// NewVerifyingClient returns DA layer client verifying blocks submitted with dalc, after they have depth
+// confirmations (see WaitForFinality). Result of every verification is passed to onVerified.
+func NewVerifyingClient(dalc DataAvailabilityLayerClient, depth uint64, pollInterval, timeout time.Duration,
+	onVerified VerifyFunc) *VerifyingClient {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &VerifyingClient{
+		DataAvailabilityLayerClient: dalc,
+		depth:                       depth,
+		pollInterval:                pollInterval,
+		timeout:                     timeout,
+		onVerified:                  onVerified,
+		ctx:                         ctx,
+		cancel:                      cancel,
+	}
+}
+
+// Stop aborts pending verifications and stops the underlying client. Blocks submitted after Stop was called are
+// rejected with ErrClientStopped.
+func (v *VerifyingClient) Stop(ctx context.Context) error {
+	v.mtx.Lock()
+	v.stopped = true
+	v.mtx.Unlock()
+
+	v.cancel()
+	done := make(chan struct{})
+	go func() {
+		v.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+	}
+	return v.DataAvailabilityLayerClient.Stop(ctx)
+}
+
+// SubmitBlock submits block to the DA layer, and schedules its verification.
+func (v *VerifyingClient) SubmitBlock(block *types.Block) ResultSubmitBlock {
+	v.mtx.Lock()
+	if v.stopped {
+		v.mtx.Unlock()
+		return ResultSubmitBlock{Code: StatusError, Message: ErrClientStopped.Error()}
+	}
+	v.wg.Add(1)
+	v.mtx.Unlock()
+
+	res := v.DataAvailabilityLayerClient.SubmitBlock(block)
+	if res.Code != StatusSuccess {
+		v.wg.Done()
+		return res
+	}
+	go func() {
+		defer v.wg.Done()
+		v.verify(block, res.DAHeight)
+	}()
+	return res
+}
+
+// verify waits for block confirmations and checks that the same block is retrieved from DA layer.
+// Verification aborted by Stop is not reported.
+func (v *VerifyingClient) verify(block *types.Block, daHeight uint64) {
+	ctx, cancel := context.WithTimeout(v.ctx, v.timeout)
+	defer cancel()
+
+	err := WaitForFinality(ctx, v.DataAvailabilityLayerClient, block, v.depth, v.pollInterval)
+	if v.ctx.Err() != nil {
+		return
+	}
+	if err == nil {
+		err = VerifySubmission(v.DataAvailabilityLayerClient, block, daHeight)
+	}
+	v.onVerified(block, err)
+}
+
+// VerifySubmission checks that block retrieved from DA layer at daHeight, in block namespace, is byte-for-byte equal
+// to submitted block.
+//
+// ErrBlockNotIncluded is returned if there is no block with the same height at daHeight.
+// ErrBlockMismatch is returned if retrieved block is different than submitted block.
+func VerifySubmission(dalc DataAvailabilityLayerClient, block *types.Block, daHeight uint64) error {
+	expected, err := block.MarshalBinary()
+	if err != nil {
+		return fmt.Errorf("failed to serialize submitted block: %w", err)
+	}
+
+	res := dalc.RetrieveBlocks(daHeight, block.Header.NamespaceID)
+	if res.Code != StatusSuccess {
+		return fmt.Errorf("%w: DA height %d: %s", ErrRetrieveBlocks, daHeight, res.Message)
+	}
+	found := false
+	for _, b := range res.Blocks {
+		if b.Header.Height != block.Header.Height {
+			continue
+		}
+		found = true
+		retrieved, err := b.MarshalBinary()
+		if err != nil {
+			return fmt.Errorf("failed to serialize retrieved block: %w", err)
+		}
+		if bytes.Equal(expected, retrieved) {
+			return nil
+		}
+	}
+	if !found {
+		return fmt.Errorf("%w: height %d, DA height %d", ErrBlockNotIncluded, block.Header.Height, daHeight)
+	}
+	return fmt.Errorf("%w: height %d, DA height %d", ErrBlockMismatch, block.Header.Height, daHeight)
+}
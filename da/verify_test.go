@@ -0,0 +1,165 @@
+package da_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lazyledger/lazyledger-core/libs/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lazyledger/optimint/da"
+	"github.com/lazyledger/optimint/da/mock"
+	"github.com/lazyledger/optimint/types"
+)
+
+// corruptingDA is a mock DA layer client that modifies or drops blocks on retrieval.
+type corruptingDA struct {
+	mock.MockDataAvailabilityLayerClient
+
+	corrupt bool
+	drop    bool
+}
+
+func (c *corruptingDA) RetrieveBlocks(daHeight uint64, namespaceID [8]byte) da.ResultRetrieveBlocks {
+	res := c.MockDataAvailabilityLayerClient.RetrieveBlocks(daHeight, namespaceID)
+	if c.drop {
+		res.Blocks = nil
+	}
+	if c.corrupt {
+		for i, b := range res.Blocks {
+			corrupted := *b
+			corrupted.Data.Txs = append(types.Txs{}, b.Data.Txs...)
+			corrupted.Data.Txs[0] = types.Tx("corrupted")
+			res.Blocks[i] = &corrupted
+		}
+	}
+	return res
+}
+
+func TestVerifyingClient(t *testing.T) {
+	cases := []struct {
+		name        string
+		corrupt     bool
+		drop        bool
+		expectedErr error
+	}{
+		{"valid", false, false, nil},
+		{"corrupted", true, false, da.ErrBlockMismatch},
+		{"dropped", false, true, da.ErrBlockNotIncluded},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			dalc := &corruptingDA{corrupt: c.corrupt, drop: c.drop}
+			require.NoError(dalc.Init(nil, log.TestingLogger()))
+			dalc.AdvanceHeight()
+			results := make(chan error, 1)
+			verifying := da.NewVerifyingClient(dalc, 2, time.Millisecond, time.Second, func(_ *types.Block, err error) {
+				results <- err
+			})
+			require.NoError(verifying.Start(context.Background()))
+			defer func() {
+				require.NoError(verifying.Stop(context.Background()))
+			}()
+
+			// other blocks at the same DA height are ignored
+			other := &types.Block{Header: types.Header{Height: 1}, Data: types.Data{Txs: types.Txs{types.Tx("other")}}}
+			require.Equal(da.StatusSuccess, dalc.SubmitBlock(other).Code)
+
+			block := &types.Block{Header: types.Header{Height: 2}, Data: types.Data{Txs: types.Txs{types.Tx("tx1"), types.Tx("tx2")}}}
+			res := verifying.SubmitBlock(block)
+			assert.Equal(da.StatusSuccess, res.Code)
+			assert.Equal(uint64(1), res.DAHeight)
+
+			// block is not verified before it has 2 confirmations
+			select {
+			case err := <-results:
+				t.Fatalf("block verified before confirmation: %v", err)
+			case <-time.After(20 * time.Millisecond):
+			}
+			dalc.AdvanceHeight()
+
+			var err error
+			select {
+			case err = <-results:
+			case <-time.After(time.Second):
+				t.Fatal("block not verified")
+			}
+			if c.expectedErr == nil {
+				assert.NoError(err)
+			} else {
+				assert.True(errors.Is(err, c.expectedErr), err)
+			}
+			assert.Equal(err, da.VerifySubmission(dalc, block, res.DAHeight))
+		})
+	}
+}
+
+func TestVerifyingClientTimeout(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	dalc := &mock.MockDataAvailabilityLayerClient{}
+	require.NoError(dalc.Init(nil, log.TestingLogger()))
+	results := make(chan error, 1)
+	verifying := da.NewVerifyingClient(dalc, 2, time.Millisecond, 50*time.Millisecond, func(_ *types.Block, err error) {
+		results <- err
+	})
+
+	// block never gets second confirmation
+	require.Equal(da.StatusSuccess, verifying.SubmitBlock(&types.Block{Header: types.Header{Height: 1}}).Code)
+	select {
+	case err := <-results:
+		assert.True(errors.Is(err, context.DeadlineExceeded), err)
+	case <-time.After(time.Second):
+		t.Fatal("verification didn't time out")
+	}
+
+	// pending verification is aborted on Stop and not reported
+	require.Equal(da.StatusSuccess, verifying.SubmitBlock(&types.Block{Header: types.Header{Height: 2}}).Code)
+	require.NoError(verifying.Stop(context.Background()))
+	assert.Empty(results)
+}
+
+func TestVerifyingClientStop(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	dalc := &mock.MockDataAvailabilityLayerClient{}
+	require.NoError(dalc.Init(nil, log.TestingLogger()))
+	verifying := da.NewVerifyingClient(dalc, 2, time.Millisecond, time.Second, func(*types.Block, error) {})
+	require.NoError(verifying.Start(context.Background()))
+
+	// submissions racing with Stop either succeed or are rejected
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(height uint64) {
+			defer wg.Done()
+			for ; ; height += 10 {
+				if verifying.SubmitBlock(&types.Block{Header: types.Header{Height: height}}).Code != da.StatusSuccess {
+					return
+				}
+			}
+		}(uint64(i + 1))
+	}
+	time.Sleep(10 * time.Millisecond)
+	require.NoError(verifying.Stop(context.Background()))
+	wg.Wait()
+
+	// block submitted after Stop is not passed to DA layer
+	block := &types.Block{Header: types.Header{Height: 1000000}}
+	res := verifying.SubmitBlock(block)
+	assert.Equal(da.StatusError, res.Code)
+	assert.Equal(da.ErrClientStopped.Error(), res.Message)
+	for _, b := range dalc.RetrieveBlocks(0, block.Header.NamespaceID).Blocks {
+		assert.NotEqual(block.Header.Height, b.Header.Height)
+	}
+}
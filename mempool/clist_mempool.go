@@ -6,6 +6,7 @@ import (
 	"context"
 	"crypto/sha256"
 	"fmt"
+	"sort"
 	"sync"
 	"sync/atomic"
 
@@ -20,6 +21,8 @@ import (
 	"github.com/lazyledger/lazyledger-core/p2p"
 	"github.com/lazyledger/lazyledger-core/proxy"
 	"github.com/lazyledger/lazyledger-core/types"
+
+	optimint "github.com/lazyledger/optimint/types"
 )
 
 // TxKeySize is the size of the transaction key index
@@ -50,6 +53,12 @@ type CListMempool struct {
 	updateMtx tmsync.RWMutex
 	preCheck  PreCheckFunc
 	postCheck PostCheckFunc
+	evicted   EvictionFunc
+
+	// sequences is nil, unless sequence based admission is enabled
+	sequences *sequenceAdmission
+	// canonicalOrder makes reaping independent of transaction arrival order
+	canonicalOrder bool
 
 	wal          *auto.AutoFile // a log of mempool txs
 	txs          *clist.CList   // concurrent linked-list of good txs
@@ -132,6 +141,23 @@ func WithPostCheck(f PostCheckFunc) CListMempoolOption {
 	return func(mem *CListMempool) { mem.postCheck = f }
 }
 
+// WithEvictionCallback sets the function called for every transaction accepted by the application in CheckTx, but
+// dropped from the mempool without being included in a block. It may be called with mempool lock held, so it must
+// not call mempool methods.
+func WithEvictionCallback(f EvictionFunc) CListMempoolOption {
+	return func(mem *CListMempool) { mem.evicted = f }
+}
+
+// WithCanonicalReapOrder makes reaping deterministic: transactions are reaped in order of their keys (see TxKey),
+// instead of order of arrival. Mempools containing the same transactions reap the same blocks, regardless of the order
+// in which transactions were received.
+//
+// Canonical order doesn't preserve ordering of transactions from the same sender, so it shouldn't be used with
+// applications that require it (for example, with WithSequenceAdmission).
+func WithCanonicalReapOrder() CListMempoolOption {
+	return func(mem *CListMempool) { mem.canonicalOrder = true }
+}
+
 // WithMetrics sets the metrics.
 func WithMetrics(metrics *Metrics) CListMempoolOption {
 	return func(mem *CListMempool) { mem.metrics = metrics }
@@ -206,6 +232,13 @@ func (mem *CListMempool) Flush() {
 		mem.txsMap.Delete(key)
 		return true
 	})
+
+	if mem.sequences != nil {
+		mem.sequences.reset()
+	}
+
+	mem.metrics.Size.Set(0)
+	mem.metrics.SizeBytes.Set(0)
 }
 
 // TxsFront returns the first transaction in the ordered list for peer
@@ -296,7 +329,7 @@ func (mem *CListMempool) CheckTx(tx types.Tx, cb func(*abci.Response), txInfo Tx
 		mem.cache.Remove(tx)
 		return err
 	}
-	reqRes.SetCallback(mem.reqResCb(tx, txInfo.SenderID, txInfo.SenderP2PID, cb))
+	reqRes.SetCallback(mem.reqResCb(tx, txInfo.SenderID, txInfo.SenderP2PID, txInfo.GossipTTL, cb))
 
 	return nil
 }
@@ -335,6 +368,7 @@ func (mem *CListMempool) reqResCb(
 	tx []byte,
 	peerID uint16,
 	peerP2PID p2p.ID,
+	gossipTTL uint8,
 	externalCb func(*abci.Response),
 ) func(res *abci.Response) {
 	return func(res *abci.Response) {
@@ -343,7 +377,7 @@ func (mem *CListMempool) reqResCb(
 			panic("recheck cursor is not nil in reqResCb")
 		}
 
-		mem.resCbFirstTime(tx, peerID, peerP2PID, res)
+		mem.resCbFirstTime(tx, peerID, peerP2PID, gossipTTL, res)
 
 		// update metrics
 		mem.metrics.Size.Set(float64(mem.Size()))
@@ -355,13 +389,64 @@ func (mem *CListMempool) reqResCb(
 	}
 }
 
+// notifyEvicted passes tx dropped from mempool to eviction callback, if it's set.
+func (mem *CListMempool) notifyEvicted(tx types.Tx, reason error) {
+	if mem.evicted != nil {
+		mem.evicted(tx, reason)
+	}
+}
+
+// rejectCheckTx marks response of tx accepted by the application as rejected by the mempool, so callers of CheckTx
+// (eg. the RPC) don't report it as successful.
+func rejectCheckTx(res *abci.ResponseCheckTx, err error) {
+	res.Code = CodeTypeRejected
+	res.Codespace = CodespaceMempool
+	res.Log = err.Error()
+}
+
 // Called from:
 //  - resCbFirstTime (lock not held) if tx is valid
 func (mem *CListMempool) addTx(memTx *MempoolTx) {
 	e := mem.txs.PushBack(memTx)
 	mem.txsMap.Store(TxKey(memTx.Tx), e)
-	atomic.AddInt64(&mem.txsBytes, int64(len(memTx.Tx)))
+	txsBytes := atomic.AddInt64(&mem.txsBytes, int64(len(memTx.Tx)))
 	mem.metrics.TxSizeBytes.Observe(float64(len(memTx.Tx)))
+	mem.metrics.SizeBytes.Set(float64(txsBytes))
+	mem.metrics.AddedTxs.Add(1)
+}
+
+// addGoodTx adds tx accepted by the application to the mempool.
+func (mem *CListMempool) addGoodTx(memTx *MempoolTx) {
+	mem.addTx(memTx)
+	mem.logger.Info("Added good transaction",
+		"tx", txID(memTx.Tx),
+		"height", memTx.height,
+		"total", mem.Size(),
+	)
+}
+
+// addIfNotFull adds tx admitted in sequence order to the mempool, unless the mempool is full.
+func (mem *CListMempool) addIfNotFull(memTx *MempoolTx) error {
+	if err := mem.isFull(len(memTx.Tx)); err != nil {
+		return err
+	}
+	mem.addGoodTx(memTx)
+	return nil
+}
+
+// dropReleased drops queued tx, released after its sequence gap was filled, that can't be added to the mempool.
+func (mem *CListMempool) dropReleased(memTx *MempoolTx, err error) {
+	mem.logger.Error("Dropped queued transaction", "tx", txID(memTx.Tx), "err", err)
+	mem.cache.Remove(memTx.Tx)
+	mem.metrics.EvictedTxs.Add(1)
+	mem.notifyEvicted(memTx.Tx, err)
+}
+
+// forgetSequence releases the sequence of tx that left the mempool, if sequence based admission is enabled.
+func (mem *CListMempool) forgetSequence(memTx *MempoolTx, committed bool) {
+	if mem.sequences != nil {
+		mem.sequences.remove(memTx, committed)
+	}
 }
 
 // Called from:
@@ -371,7 +456,9 @@ func (mem *CListMempool) removeTx(tx types.Tx, elem *clist.CElement, removeFromC
 	mem.txs.Remove(elem)
 	elem.DetachPrev()
 	mem.txsMap.Delete(TxKey(tx))
-	atomic.AddInt64(&mem.txsBytes, int64(-len(tx)))
+	txsBytes := atomic.AddInt64(&mem.txsBytes, int64(-len(tx)))
+	mem.metrics.Size.Set(float64(mem.Size()))
+	mem.metrics.SizeBytes.Set(float64(txsBytes))
 
 	if removeFromCache {
 		mem.cache.Remove(tx)
@@ -384,6 +471,9 @@ func (mem *CListMempool) RemoveTxByKey(txKey [TxKeySize]byte, removeFromCache bo
 		memTx := e.(*clist.CElement).Value.(*MempoolTx)
 		if memTx != nil {
 			mem.removeTx(memTx.Tx, e.(*clist.CElement), removeFromCache)
+			mem.forgetSequence(memTx, false)
+			mem.metrics.EvictedTxs.Add(1)
+			mem.notifyEvicted(memTx.Tx, ErrTxRemoved)
 		}
 	}
 }
@@ -412,6 +502,7 @@ func (mem *CListMempool) resCbFirstTime(
 	tx []byte,
 	peerID uint16,
 	peerP2PID p2p.ID,
+	gossipTTL uint8,
 	res *abci.Response,
 ) {
 	switch r := res.Value.(type) {
@@ -427,22 +518,36 @@ func (mem *CListMempool) resCbFirstTime(
 				// remove from cache (mempool might have a space later)
 				mem.cache.Remove(tx)
 				mem.logger.Error(err.Error())
+				mem.notifyEvicted(tx, err)
+				rejectCheckTx(r.CheckTx, err)
 				return
 			}
 
 			memTx := &MempoolTx{
 				height:    mem.height,
 				gasWanted: r.CheckTx.GasWanted,
+				gossipTTL: gossipTTL,
 				Tx:        tx,
 			}
 			memTx.senders.Store(peerID, true)
-			mem.addTx(memTx)
-			mem.logger.Info("Added good transaction",
-				"tx", txID(tx),
-				"res", r,
-				"height", memTx.height,
-				"total", mem.Size(),
-			)
+
+			if mem.sequences == nil {
+				mem.addGoodTx(memTx)
+			} else {
+				queued, err := mem.sequences.admit(memTx, r.CheckTx, mem.addIfNotFull, mem.dropReleased)
+				if err != nil {
+					mem.logger.Info("Rejected transaction", "tx", txID(tx), "peerID", peerP2PID, "err", err)
+					mem.metrics.FailedTxs.Add(1)
+					mem.cache.Remove(tx)
+					mem.notifyEvicted(tx, err)
+					rejectCheckTx(r.CheckTx, err)
+					return
+				}
+				if queued {
+					mem.logger.Info("Queued transaction with sequence gap", "tx", txID(tx), "peerID", peerP2PID)
+					return
+				}
+			}
 			mem.notifyTxsAvailable()
 		} else {
 			// ignore bad transaction
@@ -451,6 +556,9 @@ func (mem *CListMempool) resCbFirstTime(
 			mem.metrics.FailedTxs.Add(1)
 			// remove from cache (it might be good later)
 			mem.cache.Remove(tx)
+			if postCheckErr != nil && r.CheckTx.Code == abci.CodeTypeOK {
+				rejectCheckTx(r.CheckTx, postCheckErr)
+			}
 		}
 	default:
 		// ignore other messages
@@ -483,6 +591,9 @@ func (mem *CListMempool) resCbRecheck(req *abci.Request, res *abci.Response) {
 			mem.logger.Info("Tx is no longer valid", "tx", txID(tx), "res", r, "err", postCheckErr)
 			// NOTE: we remove tx from the cache because it might be good later
 			mem.removeTx(tx, mem.recheckCursor, true)
+			mem.forgetSequence(memTx, false)
+			mem.metrics.EvictedTxs.Add(1)
+			mem.notifyEvicted(tx, ErrTxInvalidated)
 		}
 		if mem.recheckCursor == mem.recheckEnd {
 			mem.recheckCursor = nil
@@ -533,9 +644,7 @@ func (mem *CListMempool) ReapMaxBytesMaxGas(maxBytes, maxGas int64) types.Txs {
 	// size per tx, and set the initial capacity based off of that.
 	// txs := make([]types.Tx, 0, tmmath.MinInt(mem.txs.Len(), max/mem.avgTxSize))
 	txs := make([]types.Tx, 0, mem.txs.Len())
-	for e := mem.txs.Front(); e != nil; e = e.Next() {
-		memTx := e.Value.(*MempoolTx)
-
+	for _, memTx := range mem.reapOrder() {
 		dataSize := types.ComputeProtoSizeForTxs(append(txs, memTx.Tx))
 
 		// Check total size requirement
@@ -566,13 +675,36 @@ func (mem *CListMempool) ReapMaxTxs(max int) types.Txs {
 	}
 
 	txs := make([]types.Tx, 0, tmmath.MinInt(mem.txs.Len(), max))
-	for e := mem.txs.Front(); e != nil && len(txs) <= max; e = e.Next() {
-		memTx := e.Value.(*MempoolTx)
+	for _, memTx := range mem.reapOrder() {
+		if len(txs) > max {
+			break
+		}
 		txs = append(txs, memTx.Tx)
 	}
 	return txs
 }
 
+// reapOrder returns all transactions in mempool, in order in which they should be reaped.
+func (mem *CListMempool) reapOrder() []*MempoolTx {
+	memTxs := make([]*MempoolTx, 0, mem.txs.Len())
+	for e := mem.txs.Front(); e != nil; e = e.Next() {
+		memTxs = append(memTxs, e.Value.(*MempoolTx))
+	}
+	if !mem.canonicalOrder {
+		return memTxs
+	}
+
+	keys := make(map[*MempoolTx][TxKeySize]byte, len(memTxs))
+	for _, memTx := range memTxs {
+		keys[memTx] = TxKey(memTx.Tx)
+	}
+	sort.Slice(memTxs, func(i, j int) bool {
+		ki, kj := keys[memTxs[i]], keys[memTxs[j]]
+		return bytes.Compare(ki[:], kj[:]) < 0
+	})
+	return memTxs
+}
+
 // Lock() must be help by the caller during execution.
 func (mem *CListMempool) Update(
 	height int64,
@@ -613,6 +745,7 @@ func (mem *CListMempool) Update(
 		// https://github.com/tendermint/tendermint/issues/3322.
 		if e, ok := mem.txsMap.Load(TxKey(tx)); ok {
 			mem.removeTx(tx, e.(*clist.CElement), false)
+			mem.forgetSequence(e.(*clist.CElement).Value.(*MempoolTx), true)
 		}
 	}
 
@@ -672,8 +805,12 @@ func (mem *CListMempool) recheckTxs() {
 type MempoolTx struct {
 	height    int64    // height that this tx had been validated in
 	gasWanted int64    // amount of gas this tx states it will require
+	gossipTTL uint8    // TTL of received tx, 0 for local txs
 	Tx        types.Tx //
 
+	// sequence is set if tx was admitted in sequence order
+	sequence *txSequence
+
 	// ids of peers who've sent us this tx (as a map for quick lookups).
 	// senders: PeerID -> bool
 	senders sync.Map
@@ -684,6 +821,11 @@ func (memTx *MempoolTx) Height() int64 {
 	return atomic.LoadInt64(&memTx.height)
 }
 
+// GossipTTL returns the TTL the transaction was received with, or 0 if transaction wasn't received via gossip.
+func (memTx *MempoolTx) GossipTTL() uint8 {
+	return memTx.gossipTTL
+}
+
 //--------------------------------------------------------------------------------
 
 type txCache interface {
@@ -771,7 +913,7 @@ func (nopTxCache) Remove(types.Tx)    {}
 
 // TxKey is the fixed length array hash used as the key in maps.
 func TxKey(tx types.Tx) [TxKeySize]byte {
-	return sha256.Sum256(tx)
+	return optimint.Tx(tx).Hash()
 }
 
 // txID is the hex encoded hash of the bytes as a types.Tx.
@@ -5,6 +5,7 @@ import (
 	"crypto/rand"
 	"crypto/sha256"
 	"encoding/binary"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -12,6 +13,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/go-kit/kit/metrics/generic"
 	"github.com/gogo/protobuf/proto"
 	gogotypes "github.com/gogo/protobuf/types"
 	"github.com/stretchr/testify/assert"
@@ -177,6 +179,156 @@ func TestMempoolFilters(t *testing.T) {
 	}
 }
 
+func TestPostCheckMinGasPrice(t *testing.T) {
+	feeEvent := func(key, value string) []abci.Event {
+		return []abci.Event{{Type: "tx", Attributes: []abci.EventAttribute{{Key: []byte(key), Value: []byte(value)}}}}
+	}
+
+	tests := []struct {
+		name        string
+		minGasPrice float64
+		denom       string
+		gasWanted   int64
+		events      []abci.Event
+		admitted    bool
+	}{
+		{"no min gas price", 0, "", 100, nil, true},
+		{"no min gas price, no gas wanted", 0, "", 0, nil, true},
+		{"above threshold", 0.5, "", 100, feeEvent("fee", "60"), true},
+		{"at threshold", 0.5, "", 100, feeEvent("fee", "50"), true},
+		{"below threshold", 0.5, "", 100, feeEvent("fee", "49"), false},
+		{"with denomination", 0.5, "", 100, feeEvent("fee", "50stake"), true},
+		{"with denomination, below threshold", 0.5, "", 100, feeEvent("fee", "49stake"), false},
+		{"no fee", 0.5, "", 100, nil, false},
+		{"other attribute", 0.5, "", 100, feeEvent("tip", "100"), false},
+		{"invalid fee", 0.5, "", 100, feeEvent("fee", "stake"), false},
+		{"invalid coin", 0.5, "", 100, feeEvent("fee", "100stake,atom"), false},
+		{"no gas wanted", 0.5, "", 0, nil, false},
+		{"negative gas wanted", 0.5, "", -1, feeEvent("fee", "100"), false},
+		{"multiple coins, same denomination", 0.5, "", 100, feeEvent("fee", "30stake,20stake"), true},
+		{"multiple denominations", 0.5, "", 100, feeEvent("fee", "50stake,10atom"), false},
+		{"configured denomination", 0.5, "stake", 100, feeEvent("fee", "10atom, 50stake"), true},
+		{"configured denomination, below threshold", 0.5, "stake", 100, feeEvent("fee", "100atom,49stake"), false},
+		{"configured denomination, not paid", 0.5, "stake", 100, feeEvent("fee", "100atom"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := &abci.ResponseCheckTx{GasWanted: tt.gasWanted, Events: tt.events}
+			err := PostCheckMinGasPrice(tt.minGasPrice, "tx.fee", tt.denom)(types.Tx("tx"), res)
+			if tt.admitted {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestPostCheckRejectedResponse(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	cc := proxy.NewLocalClientCreator(kvstore.NewApplication())
+	mempool, cleanup := newMempoolWithApp(cc)
+	defer cleanup()
+	WithPostCheck(PostCheckMinGasPrice(1, "tx.fee", ""))(mempool)
+
+	var res *abci.ResponseCheckTx
+	require.NoError(mempool.CheckTx(types.Tx("tx"), func(r *abci.Response) {
+		res = r.GetCheckTx()
+	}, TxInfo{}))
+	require.NotNil(res)
+	assert.Equal(CodeTypeRejected, res.Code)
+	assert.Equal(CodespaceMempool, res.Codespace)
+	assert.Contains(res.Log, ErrGasPriceTooLow.Error())
+	assert.Zero(mempool.Size())
+}
+
+func TestEvictionCallback(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	cc := proxy.NewLocalClientCreator(kvstore.NewApplication())
+	mempool, cleanup := newMempoolWithApp(cc)
+	defer cleanup()
+
+	evicted := make(map[string]error)
+	WithEvictionCallback(func(tx types.Tx, reason error) {
+		evicted[string(tx)] = reason
+	})(mempool)
+
+	for _, tx := range []string{"removed", "invalidated", "valid"} {
+		require.NoError(mempool.CheckTx(types.Tx(tx), nil, TxInfo{}))
+	}
+	require.Equal(3, mempool.Size())
+	assert.Empty(evicted)
+
+	mempool.RemoveTxByKey(TxKey(types.Tx("removed")), true)
+
+	// recheck with new post check function invalidates one of transactions
+	mempool.Lock()
+	err := mempool.Update(1, nil, nil, nil, func(tx types.Tx, _ *abci.ResponseCheckTx) error {
+		if string(tx) == "invalidated" {
+			return errors.New("invalid")
+		}
+		return nil
+	})
+	mempool.Unlock()
+	require.NoError(err)
+
+	assert.Equal(1, mempool.Size())
+	assert.Equal(map[string]error{"removed": ErrTxRemoved, "invalidated": ErrTxInvalidated}, evicted)
+}
+
+func TestCanonicalReapOrder(t *testing.T) {
+	txs := make(types.Txs, 20)
+	for i := range txs {
+		txs[i] = types.Tx(fmt.Sprintf("tx-%d", i))
+	}
+	reversed := make(types.Txs, len(txs))
+	for i, tx := range txs {
+		reversed[len(txs)-1-i] = tx
+	}
+
+	// reap returns data reaped from mempool, that received txs in given order
+	reap := func(t *testing.T, txs types.Txs, options ...CListMempoolOption) (types.Txs, types.Txs) {
+		cc := proxy.NewLocalClientCreator(kvstore.NewApplication())
+		mempool, cleanup := newMempoolWithApp(cc)
+		t.Cleanup(cleanup)
+		for _, option := range options {
+			option(mempool)
+		}
+		for _, tx := range txs {
+			require.NoError(t, mempool.CheckTx(tx, nil, TxInfo{}))
+		}
+		// size of 5 txs
+		maxBytes := types.ComputeProtoSizeForTxs(txs[:5])
+		return mempool.ReapMaxBytesMaxGas(maxBytes, -1), mempool.ReapMaxTxs(-1)
+	}
+
+	t.Run("canonical", func(t *testing.T) {
+		assert := assert.New(t)
+
+		partial1, all1 := reap(t, txs, WithCanonicalReapOrder())
+		partial2, all2 := reap(t, reversed, WithCanonicalReapOrder())
+		assert.Len(partial1, 5)
+		assert.Equal(partial1, partial2)
+		assert.Len(all1, len(txs))
+		assert.Equal(all1, all2)
+	})
+
+	t.Run("arrival", func(t *testing.T) {
+		assert := assert.New(t)
+
+		partial1, all1 := reap(t, txs)
+		partial2, all2 := reap(t, reversed)
+		assert.Equal(txs[:5], partial1)
+		assert.Equal(reversed[:5], partial2)
+		assert.Equal(txs, all1)
+		assert.Equal(reversed, all2)
+	})
+}
+
 func TestMempoolUpdate(t *testing.T) {
 	app := kvstore.NewApplication()
 	cc := proxy.NewLocalClientCreator(app)
@@ -544,6 +696,54 @@ func TestMempoolTxsBytes(t *testing.T) {
 
 }
 
+func TestMempoolMetrics(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	app := counter.NewApplication(true)
+	cc := proxy.NewLocalClientCreator(app)
+	mempool, cleanup := newMempoolWithApp(cc)
+	defer cleanup()
+
+	metrics := &Metrics{
+		Size:         generic.NewGauge("size"),
+		SizeBytes:    generic.NewGauge("size_bytes"),
+		TxSizeBytes:  generic.NewHistogram("tx_size_bytes", 10),
+		AddedTxs:     generic.NewCounter("added_txs"),
+		FailedTxs:    generic.NewCounter("failed_txs"),
+		EvictedTxs:   generic.NewCounter("evicted_txs"),
+		RecheckTimes: generic.NewCounter("recheck_times"),
+	}
+	WithMetrics(metrics)(mempool)
+
+	// valid tx
+	validTx := make([]byte, 8)
+	binary.BigEndian.PutUint64(validTx, uint64(0))
+	err := mempool.CheckTx(validTx, nil, TxInfo{})
+	require.NoError(err)
+
+	assert.EqualValues(1, metrics.AddedTxs.(*generic.Counter).Value())
+	assert.EqualValues(0, metrics.FailedTxs.(*generic.Counter).Value())
+	assert.EqualValues(1, metrics.Size.(*generic.Gauge).Value())
+	assert.EqualValues(8, metrics.SizeBytes.(*generic.Gauge).Value())
+
+	// invalid tx (counter app accepts up to 8 bytes)
+	err = mempool.CheckTx(make([]byte, 9), nil, TxInfo{})
+	require.NoError(err)
+
+	assert.EqualValues(1, metrics.AddedTxs.(*generic.Counter).Value())
+	assert.EqualValues(1, metrics.FailedTxs.(*generic.Counter).Value())
+	assert.EqualValues(1, metrics.Size.(*generic.Gauge).Value())
+	assert.EqualValues(8, metrics.SizeBytes.(*generic.Gauge).Value())
+
+	// evicted tx
+	mempool.RemoveTxByKey(TxKey(validTx), true)
+
+	assert.EqualValues(1, metrics.EvictedTxs.(*generic.Counter).Value())
+	assert.EqualValues(0, metrics.Size.(*generic.Gauge).Value())
+	assert.EqualValues(0, metrics.SizeBytes.(*generic.Gauge).Value())
+}
+
 func checksumIt(data []byte) string {
 	h := sha256.New()
 	h.Write(data) //nolint: errcheck // ignore errcheck
@@ -8,6 +8,23 @@ import (
 var (
 	// ErrTxInCache is returned to the client if we saw tx earlier
 	ErrTxInCache = errors.New("tx already exists in cache")
+
+	// ErrSequenceTooLow is returned if tx sequence was already used by the sender
+	ErrSequenceTooLow = errors.New("tx sequence too low")
+	// ErrSequenceGap is returned if tx sequence is higher than expected and tx can't be queued
+	ErrSequenceGap = errors.New("tx sequence gap")
+	// ErrSequenceQueued is returned if tx with the same sender and sequence is already queued
+	ErrSequenceQueued = errors.New("tx with the same sequence already queued")
+
+	// ErrGasPriceTooLow is returned by PostCheckMinGasPrice if tx pays less than min gas price
+	ErrGasPriceTooLow = errors.New("gas price too low")
+	// ErrInvalidFee is returned by PostCheckMinGasPrice if tx fee can't be parsed
+	ErrInvalidFee = errors.New("invalid fee")
+
+	// ErrTxInvalidated is passed to EvictionFunc if tx was found invalid during recheck
+	ErrTxInvalidated = errors.New("tx is no longer valid")
+	// ErrTxRemoved is passed to EvictionFunc if tx was explicitly removed from mempool
+	ErrTxRemoved = errors.New("tx removed from mempool")
 )
 
 // ErrTxTooLarge means the tx is too big to be sent in a message to other peers
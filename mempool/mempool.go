@@ -3,8 +3,11 @@ package mempool
 import (
 	"context"
 	"fmt"
+	"strconv"
+	"strings"
 
 	abci "github.com/lazyledger/lazyledger-core/abci/types"
+	"github.com/lazyledger/lazyledger-core/libs/clist"
 	"github.com/lazyledger/lazyledger-core/p2p"
 	"github.com/lazyledger/lazyledger-core/types"
 )
@@ -13,6 +16,14 @@ import (
 // no peer (e.g. RPC)
 const UnknownPeerID uint16 = 0
 
+const (
+	// CodespaceMempool is the codespace of CheckTx responses of transactions rejected by the mempool.
+	CodespaceMempool = "mempool"
+	// CodeTypeRejected is the code of CheckTx response of transaction accepted by the application, but rejected by
+	// the mempool (for example by sequence based admission).
+	CodeTypeRejected uint32 = 1
+)
+
 // Mempool defines the mempool interface.
 //
 // Updates to the mempool need to be synchronized with committing a block so
@@ -74,6 +85,13 @@ type Mempool interface {
 	// TxsBytes returns the total size of all txs in the mempool.
 	TxsBytes() int64
 
+	// TxsFront returns the first transaction in the ordered list, or nil if mempool is empty.
+	// Values of list elements are of type *MempoolTx.
+	TxsFront() *clist.CElement
+
+	// TxsWaitChan returns a channel that is closed once the mempool is not empty.
+	TxsWaitChan() <-chan struct{}
+
 	// InitWAL creates a directory for the WAL file and opens a file itself. If
 	// there is an error, it will be of type *PathError.
 	InitWAL() error
@@ -95,6 +113,10 @@ type PreCheckFunc func(types.Tx) error
 // transaction doesn't require more gas than available for the block.
 type PostCheckFunc func(types.Tx, *abci.ResponseCheckTx) error
 
+// EvictionFunc is an optional callback executed when transaction accepted by the application in CheckTx is dropped
+// from mempool without being included in a block. reason describes why transaction was dropped.
+type EvictionFunc func(tx types.Tx, reason error)
+
 // TxInfo are parameters that get passed when attempting to add a tx to the
 // mempool.
 type TxInfo struct {
@@ -105,6 +127,8 @@ type TxInfo struct {
 	SenderP2PID p2p.ID
 	// Context is the optional context to cancel CheckTx
 	Context context.Context
+	// GossipTTL is the TTL of gossiped transaction, 0 for transactions submitted locally.
+	GossipTTL uint8
 }
 
 //--------------------------------------------------------------------------------
@@ -140,3 +164,78 @@ func PostCheckMaxGas(maxGas int64) PostCheckFunc {
 		return nil
 	}
 }
+
+// PostCheckMinGasPrice checks that the price of gas wanted by the transaction is greater or equal to minGasPrice.
+// Returns nil if minGasPrice is 0. Transactions not wanting any gas are rejected, as their gas price can't be
+// determined.
+//
+// The fee paid by the transaction is read from CheckTx event attribute identified by feeKey, in the form
+// "<event type>.<attribute key>". Attribute value is a comma-separated list of coins, each being an amount optionally
+// followed by denomination (for example "100stake,20atom"). If denom is set, only coins in denom are counted as fee.
+// Otherwise, fee has to be paid in a single denomination.
+func PostCheckMinGasPrice(minGasPrice float64, feeKey string, denom string) PostCheckFunc {
+	return func(tx types.Tx, res *abci.ResponseCheckTx) error {
+		if minGasPrice == 0 {
+			return nil
+		}
+		if res.GasWanted <= 0 {
+			return fmt.Errorf("%w: gas wanted %d", ErrGasPriceTooLow, res.GasWanted)
+		}
+		fee, err := checkTxFee(res, feeKey, denom)
+		if err != nil {
+			return err
+		}
+		gasPrice := float64(fee) / float64(res.GasWanted)
+		if gasPrice < minGasPrice {
+			return fmt.Errorf("%w: gas price %v (fee %d, gas wanted %d), min gas price %v",
+				ErrGasPriceTooLow, gasPrice, fee, res.GasWanted, minGasPrice)
+		}
+		return nil
+	}
+}
+
+// checkTxFee returns the fee from CheckTx event attribute identified by feeKey (see PostCheckMinGasPrice).
+// Missing fee is reported as 0.
+func checkTxFee(res *abci.ResponseCheckTx, feeKey string, denom string) (uint64, error) {
+	for _, event := range res.Events {
+		for _, attr := range event.Attributes {
+			if event.Type+"."+string(attr.Key) != feeKey {
+				continue
+			}
+			var fee uint64
+			var feeDenom string
+			for i, coin := range strings.Split(string(attr.Value), ",") {
+				amount, coinDenom, err := parseCoin(strings.TrimSpace(coin))
+				if err != nil {
+					return 0, err
+				}
+				switch {
+				case denom != "" && coinDenom != denom:
+					continue
+				case denom == "" && i > 0 && coinDenom != feeDenom:
+					return 0, fmt.Errorf("%w: multiple denominations in %q", ErrInvalidFee, attr.Value)
+				}
+				feeDenom = coinDenom
+				if fee+amount < fee {
+					return 0, fmt.Errorf("%w: overflow in %q", ErrInvalidFee, attr.Value)
+				}
+				fee += amount
+			}
+			return fee, nil
+		}
+	}
+	return 0, nil
+}
+
+// parseCoin splits coin into amount and denomination.
+func parseCoin(coin string) (uint64, string, error) {
+	end := 0
+	for end < len(coin) && coin[end] >= '0' && coin[end] <= '9' {
+		end++
+	}
+	amount, err := strconv.ParseUint(coin[:end], 10, 64)
+	if err != nil {
+		return 0, "", fmt.Errorf("%w: %q", ErrInvalidFee, coin)
+	}
+	return amount, coin[end:], nil
+}
@@ -0,0 +1,182 @@
+package mempool
+
+import (
+	"fmt"
+
+	abci "github.com/lazyledger/lazyledger-core/abci/types"
+	tmsync "github.com/lazyledger/lazyledger-core/libs/sync"
+	"github.com/lazyledger/lazyledger-core/types"
+)
+
+// SequenceFunc extracts account sequence information of a transaction accepted by the application in CheckTx.
+//
+// It returns the sender account, the sequence of the transaction and the next sequence expected by the application
+// for the sender (not counting transactions in the mempool). Information can be taken from the transaction itself
+// or from the CheckTx response (for example from its events). ok is false if transaction is not subject to sequence
+// based admission.
+type SequenceFunc func(tx types.Tx, res *abci.ResponseCheckTx) (sender string, seq uint64, expected uint64, ok bool)
+
+// WithSequenceAdmission enables admission of transactions in account sequence order.
+//
+// Transaction with sequence lower than next expected sequence of the sender is rejected. Transaction with sequence
+// higher than expected (creating a sequence gap) is rejected if queueSize is 0, otherwise it's queued until the gap
+// is filled. At most queueSize transactions are queued at any time; gapped transactions are rejected when queue is
+// full. Queued transactions are not included in mempool size and are not available for reaping.
+//
+// Rejected transactions are reported to CheckTx callback with CodeTypeRejected. If transaction leaves the mempool
+// without being committed (it's invalidated during recheck, removed or evicted), its sequence is expected again.
+func WithSequenceAdmission(f SequenceFunc, queueSize int) CListMempoolOption {
+	return func(mem *CListMempool) {
+		mem.sequences = &sequenceAdmission{
+			seqFn:     f,
+			queueSize: queueSize,
+			senders:   make(map[string]*senderSequences),
+		}
+	}
+}
+
+// SequenceAdmission returns true if transactions are admitted in sequence order (see WithSequenceAdmission).
+func (mem *CListMempool) SequenceAdmission() bool {
+	return mem.sequences != nil
+}
+
+// txSequence identifies a transaction admitted in sequence order.
+type txSequence struct {
+	sender string
+	seq    uint64
+}
+
+// senderSequences is the admission state of a single sender.
+//
+// It's tracked only while sender has transactions in mempool or in queue, so the number of tracked senders is
+// bounded by mempool size and queue size.
+type senderSequences struct {
+	next    uint64
+	pending int // number of admitted transactions in mempool
+	queued  map[uint64]*MempoolTx
+}
+
+// sequenceAdmission tracks next expected sequence of every sender and transactions waiting for a sequence gap to
+// be filled.
+type sequenceAdmission struct {
+	seqFn     SequenceFunc
+	queueSize int
+
+	mtx     tmsync.Mutex
+	senders map[string]*senderSequences
+	nQueued int
+}
+
+// admit decides about admission of memTx, accepted by the application with response res.
+//
+// Admitted transactions are passed to add in sequence order: memTx, followed by all queued transactions of the
+// sender that follow it without a gap. If add fails for a queued transaction, the transaction is passed to drop
+// and the transactions following it stay queued. admit returns true if memTx is queued. If memTx is rejected
+// (including a failure of add), an error is returned.
+func (s *sequenceAdmission) admit(memTx *MempoolTx, res *abci.ResponseCheckTx,
+	add func(*MempoolTx) error, drop func(*MempoolTx, error)) (bool, error) {
+	sender, seq, expected, ok := s.seqFn(memTx.Tx, res)
+	if !ok {
+		return false, add(memTx)
+	}
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	state, ok := s.senders[sender]
+	if !ok {
+		state = &senderSequences{queued: make(map[uint64]*MempoolTx)}
+	}
+	if expected > state.next {
+		state.next = expected
+	}
+
+	switch {
+	case seq < state.next:
+		return false, fmt.Errorf("%w: sender %s, sequence %d, expected %d", ErrSequenceTooLow, sender, seq, state.next)
+	case seq > state.next:
+		if s.nQueued >= s.queueSize {
+			return false, fmt.Errorf("%w: sender %s, sequence %d, expected %d", ErrSequenceGap, sender, seq, state.next)
+		}
+		if _, ok := state.queued[seq]; ok {
+			return false, fmt.Errorf("%w: sender %s, sequence %d", ErrSequenceQueued, sender, seq)
+		}
+		memTx.sequence = &txSequence{sender: sender, seq: seq}
+		state.queued[seq] = memTx
+		s.nQueued++
+		s.senders[sender] = state
+		return true, nil
+	}
+
+	if err := add(memTx); err != nil {
+		return false, err
+	}
+	memTx.sequence = &txSequence{sender: sender, seq: seq}
+	state.pending++
+	s.senders[sender] = state
+
+	// release all queued transactions following memTx without a gap
+	for state.next = seq + 1; ; state.next++ {
+		queuedTx, ok := state.queued[state.next]
+		if !ok {
+			break
+		}
+		delete(state.queued, state.next)
+		s.nQueued--
+		if err := add(queuedTx); err != nil {
+			drop(queuedTx, err)
+			break
+		}
+		state.pending++
+	}
+	return false, nil
+}
+
+// remove forgets memTx, which left the mempool. If memTx wasn't committed, its sequence is expected again.
+func (s *sequenceAdmission) remove(memTx *MempoolTx, committed bool) {
+	if memTx.sequence == nil {
+		return
+	}
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	state, ok := s.senders[memTx.sequence.sender]
+	if !ok {
+		// state was reset
+		return
+	}
+	state.pending--
+	if !committed && memTx.sequence.seq < state.next {
+		state.next = memTx.sequence.seq
+	}
+	// once nothing is pending, the application knows the next expected sequence
+	if state.pending <= 0 && len(state.queued) == 0 {
+		delete(s.senders, memTx.sequence.sender)
+	}
+}
+
+// reset removes all queued transactions and forgets all tracked sequences.
+func (s *sequenceAdmission) reset() {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	s.senders = make(map[string]*senderSequences)
+	s.nQueued = 0
+}
+
+// numQueued returns number of transactions waiting for a sequence gap to be filled.
+func (s *sequenceAdmission) numQueued() int {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	return s.nQueued
+}
+
+// numSenders returns number of senders with tracked sequences.
+func (s *sequenceAdmission) numSenders() int {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	return len(s.senders)
+}
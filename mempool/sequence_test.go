@@ -0,0 +1,235 @@
+package mempool
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lazyledger/lazyledger-core/abci/example/kvstore"
+	abci "github.com/lazyledger/lazyledger-core/abci/types"
+	cfg "github.com/lazyledger/lazyledger-core/config"
+	"github.com/lazyledger/lazyledger-core/proxy"
+	"github.com/lazyledger/lazyledger-core/types"
+)
+
+type seqTx struct {
+	sender string
+	seq    uint64
+}
+
+// encode returns tx in form "sender/sequence/nonce". Nonce makes txs with the same sender and sequence distinct.
+func (tx seqTx) encode(nonce int) types.Tx {
+	return types.Tx(fmt.Sprintf("%s/%d/%d", tx.sender, tx.seq, nonce))
+}
+
+// sequenceFunc returns SequenceFunc for txs encoded by seqTx.encode. appSequences are next sequences expected by
+// the application.
+func sequenceFunc(appSequences map[string]uint64) SequenceFunc {
+	return func(tx types.Tx, res *abci.ResponseCheckTx) (string, uint64, uint64, bool) {
+		parts := strings.Split(string(tx), "/")
+		if len(parts) != 3 {
+			return "", 0, 0, false
+		}
+		seq, err := strconv.ParseUint(parts[1], 10, 64)
+		if err != nil {
+			return "", 0, 0, false
+		}
+		return parts[0], seq, appSequences[parts[0]], true
+	}
+}
+
+func TestSequenceAdmission(t *testing.T) {
+	// expected sequences, as if returned by the application
+	seqFn := sequenceFunc(map[string]uint64{"bob": 5})
+
+	cases := []struct {
+		name           string
+		queueSize      int
+		txs            []seqTx
+		expectedTxs    []seqTx
+		expectedQueued int
+	}{
+		{"in order", 10,
+			[]seqTx{{"alice", 0}, {"alice", 1}, {"alice", 2}},
+			[]seqTx{{"alice", 0}, {"alice", 1}, {"alice", 2}}, 0},
+		{"gapped, queued", 10,
+			[]seqTx{{"alice", 0}, {"alice", 2}, {"alice", 3}},
+			[]seqTx{{"alice", 0}}, 2},
+		{"gapped, queueing disabled", 0,
+			[]seqTx{{"alice", 0}, {"alice", 2}, {"alice", 1}, {"alice", 3}},
+			[]seqTx{{"alice", 0}, {"alice", 1}}, 0},
+		{"gap filled", 10,
+			[]seqTx{{"alice", 0}, {"alice", 3}, {"alice", 2}, {"alice", 1}},
+			[]seqTx{{"alice", 0}, {"alice", 1}, {"alice", 2}, {"alice", 3}}, 0},
+		{"gap partially filled", 10,
+			[]seqTx{{"alice", 2}, {"alice", 4}, {"alice", 0}, {"alice", 1}},
+			[]seqTx{{"alice", 0}, {"alice", 1}, {"alice", 2}}, 1},
+		{"queue full", 1,
+			[]seqTx{{"alice", 0}, {"alice", 2}, {"alice", 3}, {"alice", 1}},
+			[]seqTx{{"alice", 0}, {"alice", 1}, {"alice", 2}}, 0},
+		{"sequence too low", 10,
+			[]seqTx{{"alice", 0}, {"alice", 1}, {"alice", 0}, {"alice", 1}},
+			[]seqTx{{"alice", 0}, {"alice", 1}}, 0},
+		{"duplicate queued sequence", 10,
+			[]seqTx{{"alice", 1}, {"alice", 1}, {"alice", 0}},
+			[]seqTx{{"alice", 0}, {"alice", 1}}, 0},
+		{"sequence expected by app", 10,
+			[]seqTx{{"bob", 4}, {"bob", 6}, {"bob", 5}},
+			[]seqTx{{"bob", 5}, {"bob", 6}}, 0},
+		{"independent senders", 10,
+			[]seqTx{{"alice", 1}, {"bob", 5}, {"alice", 0}, {"bob", 7}},
+			[]seqTx{{"bob", 5}, {"alice", 0}, {"alice", 1}}, 1},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			cc := proxy.NewLocalClientCreator(kvstore.NewApplication())
+			mempool, cleanup := newMempoolWithApp(cc)
+			defer cleanup()
+			WithSequenceAdmission(seqFn, c.queueSize)(mempool)
+
+			txs := make(map[seqTx]types.Tx)
+			for i, tx := range c.txs {
+				encoded := tx.encode(i)
+				if _, ok := txs[tx]; !ok {
+					txs[tx] = encoded
+				}
+				require.NoError(mempool.CheckTx(encoded, nil, TxInfo{}))
+			}
+
+			expected := make(types.Txs, len(c.expectedTxs))
+			for i, tx := range c.expectedTxs {
+				expected[i] = txs[tx]
+			}
+			if len(expected) == 0 {
+				expected = nil
+			}
+			assert.Equal(expected, mempool.ReapMaxTxs(-1))
+			assert.Equal(c.expectedQueued, mempool.sequences.numQueued())
+		})
+	}
+
+	t.Run("untracked txs", func(t *testing.T) {
+		assert := assert.New(t)
+		require := require.New(t)
+
+		cc := proxy.NewLocalClientCreator(kvstore.NewApplication())
+		mempool, cleanup := newMempoolWithApp(cc)
+		defer cleanup()
+		WithSequenceAdmission(seqFn, 10)(mempool)
+
+		require.NoError(mempool.CheckTx(types.Tx("key=value"), nil, TxInfo{}))
+		require.NoError(mempool.CheckTx(seqTx{"alice", 1}.encode(0), nil, TxInfo{}))
+		assert.Equal(1, mempool.Size())
+		assert.Equal(1, mempool.sequences.numQueued())
+
+		mempool.Flush()
+		assert.Equal(0, mempool.Size())
+		assert.Equal(0, mempool.sequences.numQueued())
+	})
+}
+
+func TestSequenceAdmissionRejectedResponse(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	cc := proxy.NewLocalClientCreator(kvstore.NewApplication())
+	mempool, cleanup := newMempoolWithApp(cc)
+	defer cleanup()
+	WithSequenceAdmission(sequenceFunc(nil), 0)(mempool)
+
+	var codes []uint32
+	cb := func(res *abci.Response) {
+		codes = append(codes, res.GetCheckTx().Code)
+	}
+	require.NoError(mempool.CheckTx(seqTx{"alice", 0}.encode(0), cb, TxInfo{}))
+	require.NoError(mempool.CheckTx(seqTx{"alice", 0}.encode(1), cb, TxInfo{}))
+	require.NoError(mempool.CheckTx(seqTx{"alice", 2}.encode(2), cb, TxInfo{}))
+	assert.Equal([]uint32{abci.CodeTypeOK, CodeTypeRejected, CodeTypeRejected}, codes)
+}
+
+func TestSequenceAdmissionRemovedTxs(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	cc := proxy.NewLocalClientCreator(kvstore.NewApplication())
+	mempool, cleanup := newMempoolWithApp(cc)
+	defer cleanup()
+	WithSequenceAdmission(sequenceFunc(nil), 10)(mempool)
+
+	alice0, alice1 := seqTx{"alice", 0}.encode(0), seqTx{"alice", 1}.encode(0)
+	require.NoError(mempool.CheckTx(alice0, nil, TxInfo{}))
+	require.NoError(mempool.CheckTx(alice1, nil, TxInfo{}))
+	require.Equal(2, mempool.Size())
+
+	// removed tx makes its sequence expected again
+	mempool.RemoveTxByKey(TxKey(alice1), true)
+	resubmitted := seqTx{"alice", 1}.encode(1)
+	require.NoError(mempool.CheckTx(resubmitted, nil, TxInfo{}))
+	assert.Equal(types.Txs{alice0, resubmitted}, mempool.ReapMaxTxs(-1))
+
+	// invalidated tx makes its sequence expected again
+	mempool.Lock()
+	err := mempool.Update(1, nil, nil, nil, func(tx types.Tx, _ *abci.ResponseCheckTx) error {
+		if tx.String() == resubmitted.String() {
+			return errors.New("invalid")
+		}
+		return nil
+	})
+	mempool.Unlock()
+	require.NoError(err)
+	require.NoError(mempool.CheckTx(seqTx{"alice", 1}.encode(2), nil, TxInfo{}))
+	assert.Equal(2, mempool.Size())
+	assert.Equal(1, mempool.sequences.numSenders())
+
+	// senders are forgotten once all their txs are committed
+	committed := mempool.ReapMaxTxs(-1)
+	mempool.Lock()
+	err = mempool.Update(2, committed, abciResponses(len(committed), abci.CodeTypeOK), nil, nil)
+	mempool.Unlock()
+	require.NoError(err)
+	assert.Equal(0, mempool.Size())
+	assert.Equal(0, mempool.sequences.numSenders())
+}
+
+func TestSequenceAdmissionFullMempool(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	config := cfg.ResetTestRoot("mempool_test")
+	config.Mempool.Size = 2
+	cc := proxy.NewLocalClientCreator(kvstore.NewApplication())
+	mempool, cleanup := newMempoolWithAppAndConfig(cc, config)
+	defer cleanup()
+
+	var evicted []types.Tx
+	WithEvictionCallback(func(tx types.Tx, reason error) {
+		assert.IsType(ErrMempoolIsFull{}, reason)
+		evicted = append(evicted, tx)
+	})(mempool)
+	WithSequenceAdmission(sequenceFunc(nil), 10)(mempool)
+
+	txs := make(types.Txs, 4)
+	for i := range txs {
+		txs[i] = seqTx{"alice", uint64(i)}.encode(0)
+	}
+	require.NoError(mempool.CheckTx(txs[3], nil, TxInfo{}))
+	require.NoError(mempool.CheckTx(txs[2], nil, TxInfo{}))
+	require.NoError(mempool.CheckTx(txs[1], nil, TxInfo{}))
+	require.Equal(3, mempool.sequences.numQueued())
+
+	// filling the gap releases queued txs only while mempool has space
+	require.NoError(mempool.CheckTx(txs[0], nil, TxInfo{}))
+	assert.Equal(2, mempool.Size())
+	assert.Equal(types.Txs{txs[0], txs[1]}, mempool.ReapMaxTxs(-1))
+	assert.Equal([]types.Tx{txs[2]}, evicted)
+	assert.Equal(1, mempool.sequences.numQueued())
+}
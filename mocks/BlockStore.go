@@ -13,6 +13,20 @@ type BlockStore struct {
 	mock.Mock
 }
 
+// Base provides a mock function with given fields:
+func (_m *BlockStore) Base() uint64 {
+	ret := _m.Called()
+
+	var r0 uint64
+	if rf, ok := ret.Get(0).(func() uint64); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(uint64)
+	}
+
+	return r0
+}
+
 // Height provides a mock function with given fields:
 func (_m *BlockStore) Height() uint64 {
 	ret := _m.Called()
@@ -28,7 +42,7 @@ func (_m *BlockStore) Height() uint64 {
 }
 
 // LoadBlock provides a mock function with given fields: height
-func (_m *BlockStore) LoadBlock(height uint64) *types.Block {
+func (_m *BlockStore) LoadBlock(height uint64) (*types.Block, error) {
 	ret := _m.Called(height)
 
 	var r0 *types.Block
@@ -40,11 +54,18 @@ func (_m *BlockStore) LoadBlock(height uint64) *types.Block {
 		}
 	}
 
-	return r0
+	var r1 error
+	if rf, ok := ret.Get(1).(func(uint64) error); ok {
+		r1 = rf(height)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
 // LoadBlockByHash provides a mock function with given fields: hash
-func (_m *BlockStore) LoadBlockByHash(hash [32]byte) *types.Block {
+func (_m *BlockStore) LoadBlockByHash(hash [32]byte) (*types.Block, error) {
 	ret := _m.Called(hash)
 
 	var r0 *types.Block
@@ -56,10 +77,77 @@ func (_m *BlockStore) LoadBlockByHash(hash [32]byte) *types.Block {
 		}
 	}
 
-	return r0
+	var r1 error
+	if rf, ok := ret.Get(1).(func([32]byte) error); ok {
+		r1 = rf(hash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// LoadCommit provides a mock function with given fields: height
+func (_m *BlockStore) LoadCommit(height uint64) (*types.Commit, error) {
+	ret := _m.Called(height)
+
+	var r0 *types.Commit
+	if rf, ok := ret.Get(0).(func(uint64) *types.Commit); ok {
+		r0 = rf(height)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*types.Commit)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(uint64) error); ok {
+		r1 = rf(height)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
 // SaveBlock provides a mock function with given fields: block
-func (_m *BlockStore) SaveBlock(block *types.Block) {
-	_m.Called(block)
+func (_m *BlockStore) SaveBlock(block *types.Block) error {
+	ret := _m.Called(block)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*types.Block) error); ok {
+		r0 = rf(block)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SaveBlockWithCommit provides a mock function with given fields: block, commit
+func (_m *BlockStore) SaveBlockWithCommit(block *types.Block, commit *types.Commit) error {
+	ret := _m.Called(block, commit)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*types.Block, *types.Commit) error); ok {
+		r0 = rf(block, commit)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SaveCommit provides a mock function with given fields: height, commit
+func (_m *BlockStore) SaveCommit(height uint64, commit *types.Commit) error {
+	ret := _m.Called(height, commit)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(uint64, *types.Commit) error); ok {
+		r0 = rf(height, commit)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
 }
@@ -0,0 +1,56 @@
+package node
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/lazyledger/optimint/store"
+	optimint "github.com/lazyledger/optimint/types"
+)
+
+// ResultCommit contains signed header of a block, in the shape of Tendermint /commit endpoint result.
+type ResultCommit struct {
+	Header optimint.Header
+	// Commit signs Header. It's nil if commit for the block is not available yet.
+	Commit *optimint.Commit
+	// CanonicalCommit is true, if Commit was included in the next block. Otherwise, Commit is loaded from the store.
+	CanonicalCommit bool
+}
+
+// Commit returns header and commit of the block at given height. If height is 0, the latest block is used.
+//
+// Canonical commits are persisted as LastCommit of the next block. For the latest block, commit saved in the store
+// is returned, if available. ErrHeightNotFound is returned for heights above the latest block.
+func (n *Node) Commit(height uint64) (*ResultCommit, error) {
+	latest := n.BlockStore.Height()
+	if height == 0 {
+		height = latest
+	}
+	if height == 0 || height > latest {
+		return nil, fmt.Errorf("%w: %d (latest: %d)", ErrHeightNotFound, height, latest)
+	}
+
+	block, err := n.BlockStore.LoadBlock(height)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load block at height %d: %w", height, err)
+	}
+	res := &ResultCommit{Header: block.Header}
+
+	next, err := n.BlockStore.LoadBlock(height + 1)
+	if errors.Is(err, store.ErrKeyNotFound) {
+		commit, err := n.BlockStore.LoadCommit(height)
+		if err != nil && !errors.Is(err, store.ErrKeyNotFound) {
+			return nil, fmt.Errorf("failed to load commit at height %d: %w", height, err)
+		}
+		res.Commit = commit
+		return res, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load block at height %d: %w", height+1, err)
+	}
+	if next.LastCommit != nil {
+		res.Commit = next.LastCommit
+		res.CanonicalCommit = true
+	}
+	return res, nil
+}
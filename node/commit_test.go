@@ -0,0 +1,75 @@
+package node
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lazyledger/lazyledger-core/libs/log"
+	"github.com/lazyledger/lazyledger-core/proxy"
+	"github.com/lazyledger/lazyledger-core/types"
+
+	"github.com/lazyledger/optimint/config"
+	"github.com/lazyledger/optimint/mocks"
+	optimint "github.com/lazyledger/optimint/types"
+)
+
+func TestCommit(t *testing.T) {
+	node, err := NewNode(context.Background(), config.NodeConfig{}, getKey(t), proxy.NewLocalClientCreator(&mocks.Application{}), &types.GenesisDoc{ChainID: "test"}, log.TestingLogger())
+	require.NoError(t, err)
+
+	var blocks []*optimint.Block
+	for h := uint64(1); h <= 3; h++ {
+		block := &optimint.Block{Header: optimint.Header{Height: h, AppHash: [32]byte{byte(h)}}}
+		if h > 1 {
+			prev := blocks[h-2].Header.Hash()
+			block.Header.LastHeaderHash = prev
+			block.LastCommit = &optimint.Commit{Height: h - 1, HeaderHash: prev, Signatures: []optimint.Signature{{byte(h)}}}
+		}
+		require.NoError(t, node.BlockStore.SaveBlock(block))
+		blocks = append(blocks, block)
+	}
+
+	cases := []struct {
+		name           string
+		height         uint64
+		expectedHeader *optimint.Header
+		expectedCommit *optimint.Commit
+		expectedErr    error
+	}{
+		{"stored commit", 2, &blocks[1].Header, blocks[2].LastCommit, nil},
+		{"first block", 1, &blocks[0].Header, blocks[1].LastCommit, nil},
+		{"latest block", 3, &blocks[2].Header, nil, nil},
+		{"latest by default", 0, &blocks[2].Header, nil, nil},
+		{"beyond tip", 4, nil, nil, ErrHeightNotFound},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			res, err := node.Commit(c.height)
+			if c.expectedErr != nil {
+				assert.True(errors.Is(err, c.expectedErr), err)
+				assert.Nil(res)
+				return
+			}
+			require.NoError(err)
+			assert.Equal(*c.expectedHeader, res.Header)
+			assert.Equal(c.expectedCommit, res.Commit)
+			assert.Equal(c.expectedCommit != nil, res.CanonicalCommit)
+		})
+	}
+
+	// commit of the latest block is loaded from the store
+	tipCommit := &optimint.Commit{Height: 3, HeaderHash: blocks[2].Header.Hash(), Signatures: []optimint.Signature{{4}}}
+	require.NoError(t, node.BlockStore.SaveCommit(3, tipCommit))
+	res, err := node.Commit(0)
+	require.NoError(t, err)
+	assert.Equal(t, tipCommit, res.Commit)
+	assert.False(t, res.CanonicalCommit)
+}
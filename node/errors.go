@@ -0,0 +1,72 @@
+package node
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrUnknownDALayer is returned when DA layer client specified in configuration is not registered.
+	ErrUnknownDALayer = errors.New("unknown DA layer client")
+	// ErrNoDALayer is returned when operation requires DA layer client, but node doesn't have one.
+	ErrNoDALayer = errors.New("DA layer client not configured")
+	// ErrMonikerTooLong is returned when configured moniker is longer than config.MaxMonikerLength.
+	ErrMonikerTooLong = errors.New("moniker too long")
+	// ErrInvalidMode is returned when configured node mode is unknown.
+	ErrInvalidMode = errors.New("invalid node mode")
+	// ErrInvalidDAPollInterval is returned when configured DA poll interval is negative.
+	ErrInvalidDAPollInterval = errors.New("invalid DA poll interval")
+	// ErrIncompatibleTxOrder is returned when canonical tx order is configured together with sequence based admission
+	// of transactions, which requires transactions of the same sender to be reaped in sequence order.
+	ErrIncompatibleTxOrder = errors.New("canonical tx order can't be used with sequence admission")
+	// ErrHeightNotFound is returned when requested height is not available in block store.
+	ErrHeightNotFound = errors.New("height not found")
+	// ErrInvalidHeight is returned when block stored at given height has different height in header.
+	ErrInvalidHeight = errors.New("invalid block height")
+	// ErrInvalidLinkage is returned when block doesn't link to previous block.
+	ErrInvalidLinkage = errors.New("block doesn't link to previous block")
+	// ErrDataHashMismatch is returned when header DataHash doesn't match block data.
+	ErrDataHashMismatch = errors.New("data hash doesn't match block data")
+	// ErrInvalidGenesis is returned when genesis document is inconsistent or was modified after startup.
+	ErrInvalidGenesis = errors.New("invalid genesis document")
+	// ErrGenesisMismatch is returned when the first block of the chain is inconsistent with genesis.
+	ErrGenesisMismatch = errors.New("first block inconsistent with genesis")
+
+	// ErrGossipTxs is reported on Errors channel when transactions can't be gossiped.
+	ErrGossipTxs = errors.New("failed to gossip transactions")
+	// ErrCheckTx is reported on Errors channel when CheckTx of received transaction fails with a transient error
+	// after all retries.
+	ErrCheckTx = errors.New("failed to execute CheckTx")
+	// ErrDAVerification is reported on Errors channel when block submitted to DA layer fails verification
+	// (see config.NodeConfig.DAVerifySubmissions).
+	ErrDAVerification = errors.New("DA submission verification failed")
+	// ErrLoopStalled is reported on Errors channel when node loop is detected as stalled.
+	ErrLoopStalled = errors.New("loop stalled")
+)
+
+// OperationalError is reported on Errors channel. It matches (see errors.Is) both its Kind and its cause.
+type OperationalError struct {
+	// Kind is one of the errors reported on Errors channel (for example ErrGossipTxs).
+	Kind error
+	// Detail describes the failed operation.
+	Detail string
+	// Err is the cause of the failure; it's nil if there is no underlying error (for example, for ErrLoopStalled).
+	Err error
+}
+
+func (e *OperationalError) Error() string {
+	if e.Err == nil {
+		return fmt.Sprintf("%v: %s", e.Kind, e.Detail)
+	}
+	return fmt.Sprintf("%v: %s: %v", e.Kind, e.Detail, e.Err)
+}
+
+// Is reports whether Kind matches target; causes are matched through Unwrap.
+func (e *OperationalError) Is(target error) bool {
+	return errors.Is(e.Kind, target)
+}
+
+// Unwrap returns the cause of the failure.
+func (e *OperationalError) Unwrap() error {
+	return e.Err
+}
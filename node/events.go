@@ -0,0 +1,50 @@
+package node
+
+import (
+	"fmt"
+
+	tmjson "github.com/lazyledger/lazyledger-core/libs/json"
+	"github.com/lazyledger/lazyledger-core/libs/log"
+	tmquery "github.com/lazyledger/lazyledger-core/libs/pubsub/query"
+	"github.com/lazyledger/lazyledger-core/types"
+
+	"github.com/lazyledger/optimint/mempool"
+)
+
+// EventTxEvicted is published when transaction accepted by the application is dropped from mempool without being
+// included in a block. It's published only if TxEvictionEvents is enabled in node configuration.
+const EventTxEvicted = "TxEvicted"
+
+// EventQueryTxEvicted matches all EventTxEvicted events. Subscribers are expected to filter events by transaction
+// hash (see EventDataTxEvicted).
+var EventQueryTxEvicted = tmquery.MustParse(fmt.Sprintf("%s='%s'", types.EventTypeKey, EventTxEvicted))
+
+// EventDataTxEvicted is the data of EventTxEvicted.
+type EventDataTxEvicted struct {
+	Tx     types.Tx `json:"tx"`
+	Hash   []byte   `json:"hash"`
+	Reason string   `json:"reason"`
+}
+
+func init() {
+	tmjson.RegisterType(EventDataTxEvicted{}, "optimint/event/TxEvicted")
+}
+
+// TxEvictionEvents returns true if EventTxEvicted is published by the node.
+func (n *Node) TxEvictionEvents() bool {
+	return n.conf.TxEvictionEvents
+}
+
+// publishTxEvicted returns mempool eviction callback publishing EventTxEvicted on eventBus.
+func publishTxEvicted(eventBus *types.EventBus, logger log.Logger) mempool.EvictionFunc {
+	return func(tx types.Tx, reason error) {
+		err := eventBus.Publish(EventTxEvicted, EventDataTxEvicted{
+			Tx:     tx,
+			Hash:   tx.Hash(),
+			Reason: reason.Error(),
+		})
+		if err != nil {
+			logger.Error("failed to publish tx eviction event", "error", err)
+		}
+	}
+}
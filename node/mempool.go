@@ -1,11 +1,14 @@
 package node
 
 import (
+	"errors"
 	"fmt"
 	"math"
 
 	tmsync "github.com/lazyledger/lazyledger-core/libs/sync"
 	"github.com/libp2p/go-libp2p-core/peer"
+
+	"github.com/lazyledger/optimint/mempool"
 )
 
 const (
@@ -61,8 +64,8 @@ func (ids *mempoolIDs) Reclaim(peer peer.ID) {
 
 // GetForPeer returns an ID for the peer. ID is generated if required.
 func (ids *mempoolIDs) GetForPeer(peer peer.ID) uint16 {
-	ids.mtx.RLock()
-	defer ids.mtx.RUnlock()
+	ids.mtx.Lock()
+	defer ids.mtx.Unlock()
 
 	id, ok := ids.peerMap[peer]
 	if !ok {
@@ -81,3 +84,14 @@ func newMempoolIDs() *mempoolIDs {
 		nextID:    1, // reserve unknownPeerID(0) for mempoolReactor.BroadcastTx
 	}
 }
+
+// isTransientCheckTxError returns true if CheckTx failed for reasons unrelated to the transaction itself,
+// so it may succeed if retried. Transactions that are known, too large or rejected by mempool filters are not
+// retried.
+func isTransientCheckTxError(err error) bool {
+	if errors.Is(err, mempool.ErrTxInCache) || mempool.IsPreCheckError(err) {
+		return false
+	}
+	var tooLarge mempool.ErrTxTooLarge
+	return !errors.As(err, &tooLarge)
+}
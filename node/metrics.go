@@ -0,0 +1,90 @@
+package node
+
+import (
+	"github.com/go-kit/kit/metrics"
+	"github.com/go-kit/kit/metrics/discard"
+	"github.com/go-kit/kit/metrics/prometheus"
+	stdprometheus "github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// MetricsSubsystem is a subsystem shared by all metrics exposed by this
+	// package.
+	MetricsSubsystem = "node"
+)
+
+// Metrics contains metrics exposed by this package.
+type Metrics struct {
+	// Number of node loops that didn't make progress within LoopStallTimeout.
+	StalledLoops metrics.Gauge
+	// Number of transactions received from peers and rejected by the application in CheckTx.
+	RejectedTxs metrics.Counter
+	// Number of transactions received from peers and dropped, because they failed basic validation.
+	InvalidTxs metrics.Counter
+	// Number of transactions dropped from gossip, because publishing them failed permanently.
+	DroppedGossipTxs metrics.Counter
+	// Number of peers disconnected because they didn't respond to keep-alive ping.
+	PingFailures metrics.Counter
+	// Number of inbound streams reset because of MaxInboundStreams limit.
+	RejectedStreams metrics.Counter
+}
+
+// PrometheusMetrics returns Metrics build using Prometheus client library.
+// Optionally, labels can be provided along with their values ("foo",
+// "fooValue").
+func PrometheusMetrics(namespace string, labelsAndValues ...string) *Metrics {
+	labels := []string{}
+	for i := 0; i < len(labelsAndValues); i += 2 {
+		labels = append(labels, labelsAndValues[i])
+	}
+	return &Metrics{
+		StalledLoops: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "stalled_loops",
+			Help:      "Number of node loops that didn't make progress within stall timeout.",
+		}, labels).With(labelsAndValues...),
+		RejectedTxs: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "rejected_txs",
+			Help:      "Number of transactions received from peers and rejected by the application in CheckTx.",
+		}, labels).With(labelsAndValues...),
+		InvalidTxs: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "invalid_txs",
+			Help:      "Number of transactions received from peers and dropped, because they failed basic validation.",
+		}, labels).With(labelsAndValues...),
+		DroppedGossipTxs: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "dropped_gossip_txs",
+			Help:      "Number of transactions dropped from gossip, because publishing them failed permanently.",
+		}, labels).With(labelsAndValues...),
+		PingFailures: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "ping_failures",
+			Help:      "Number of peers disconnected because they didn't respond to keep-alive ping.",
+		}, labels).With(labelsAndValues...),
+		RejectedStreams: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "rejected_streams",
+			Help:      "Number of inbound streams reset because of inbound streams limit.",
+		}, labels).With(labelsAndValues...),
+	}
+}
+
+// NopMetrics returns no-op Metrics.
+func NopMetrics() *Metrics {
+	return &Metrics{
+		StalledLoops:     discard.NewGauge(),
+		RejectedTxs:      discard.NewCounter(),
+		InvalidTxs:       discard.NewCounter(),
+		DroppedGossipTxs: discard.NewCounter(),
+		PingFailures:     discard.NewCounter(),
+		RejectedStreams:  discard.NewCounter(),
+	}
+}
@@ -2,22 +2,47 @@ package node
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"hash/fnv"
+	"os"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	abci "github.com/lazyledger/lazyledger-core/abci/types"
 	llcfg "github.com/lazyledger/lazyledger-core/config"
 	"github.com/lazyledger/lazyledger-core/libs/clist"
 	"github.com/lazyledger/lazyledger-core/libs/log"
+	tmrand "github.com/lazyledger/lazyledger-core/libs/rand"
 	"github.com/lazyledger/lazyledger-core/libs/service"
 	corep2p "github.com/lazyledger/lazyledger-core/p2p"
 	"github.com/lazyledger/lazyledger-core/proxy"
 	"github.com/lazyledger/lazyledger-core/types"
 	"github.com/libp2p/go-libp2p-core/crypto"
+	"github.com/libp2p/go-libp2p-core/peer"
 
 	"github.com/lazyledger/optimint/config"
+	"github.com/lazyledger/optimint/conv"
+	"github.com/lazyledger/optimint/da"
+	"github.com/lazyledger/optimint/da/registry"
 	"github.com/lazyledger/optimint/mempool"
 	"github.com/lazyledger/optimint/p2p"
 	"github.com/lazyledger/optimint/store"
+	optimint "github.com/lazyledger/optimint/types"
+)
+
+const (
+	// proxyAppMinBackoff is the initial delay between attempts to start proxy app connections.
+	proxyAppMinBackoff = 100 * time.Millisecond
+	// proxyAppMaxBackoff is the maximum delay between attempts to start proxy app connections.
+	proxyAppMaxBackoff = 5 * time.Second
+
+	// waitForHeightCapacity is the capacity of NewBlock subscription used in WaitForHeight.
+	waitForHeightCapacity = 100
+	// errorsCapacity is the capacity of channel returned by Errors.
+	errorsCapacity = 100
 )
 
 type Node struct {
@@ -25,72 +50,358 @@ type Node struct {
 	eventBus *types.EventBus
 	proxyApp proxy.AppConns
 
-	genesis *types.GenesisDoc
+	genesis     *types.GenesisDoc
+	genesisHash [32]byte
 
 	conf config.NodeConfig
+	id   peer.ID
 	P2P  *p2p.Client
 
 	// TODO(tzdybal): consider extracting "mempool reactor"
-	Mempool      mempool.Mempool
-	mempoolIDs   *mempoolIDs
-	incomingTxCh chan *p2p.Tx
+	Mempool mempool.Mempool
+	// mempoolOptions are applied to the mempool when it's created (see WithMempoolOptions)
+	mempoolOptions []mempool.CListMempoolOption
+	mempoolIDs     *mempoolIDs
+	incomingTxCh   chan *p2p.Tx
+	// retryTxCh passes transactions back to mempoolReadLoop, when it's time to retry their CheckTx
+	retryTxCh chan *retryTx
+	// invalidTxs counts transactions dropped by ValidateBasic; accessed atomically
+	invalidTxs uint64
+
+	// errors buffers non-fatal operational errors, until they're consumed from Errors channel
+	errors chan error
+	// droppedErrors counts errors not reported because errors channel was full; accessed atomically
+	droppedErrors uint64
 
-	BlockStore store.BlockStore
+	BlockStore   store.BlockStore
+	BlockIndexer *store.BlockIndexer
+
+	// kv persists blocks and block index; it's nil if they're kept in memory
+	kv store.KVStore
+	// txGas returns gas of transactions, for verification of blocks against MaxGas; it's nil if gas is not verified
+	txGas optimint.GasFunc
+
+	dalc da.DataAvailabilityLayerClient
+	// daDeduplication makes DA submissions deduplicated (see WithDADeduplication)
+	daDeduplication bool
+	// auditLog is the file DA submissions are audited to; it's nil if DAAuditLog is not set
+	auditLog *os.File
+	// daSyncHeight is the DA layer height next poll of daSyncLoop starts from
+	daSyncHeight uint64
 
 	// keep context here only because of API compatibility
 	// - it's used in `OnStart` (defined in service.Service interface)
 	ctx context.Context
+
+	// cancelLoops stops all the loops started in OnStart
+	cancelLoops context.CancelFunc
+	// loops maps loop names to channels closed when loop returns
+	loops map[string]chan struct{}
+	// watchdog tracks progress of loops
+	watchdog *watchdog
+
+	metrics *Metrics
 }
 
-func NewNode(ctx context.Context, conf config.NodeConfig, nodeKey crypto.PrivKey, clientCreator proxy.ClientCreator, genesis *types.GenesisDoc, logger log.Logger) (*Node, error) {
+// Option sets an optional parameter on the Node.
+type Option func(*Node)
+
+// WithDALayerClient sets the data availability layer client used by the node.
+//
+// Client has to be initialized (Init has to be called) by the caller. Node is responsible for starting and stopping it.
+// If provided, it overrides DA layer client specified in configuration.
+func WithDALayerClient(dalc da.DataAvailabilityLayerClient) Option {
+	return func(n *Node) { n.dalc = dalc }
+}
+
+// WithDADeduplication makes the node skip submission of blocks that were already included in DA layer
+// (see da.DeduplicatingClient). DA heights of submitted blocks are persisted in the store passed to WithStore, so
+// they survive restarts; without it, they're kept in memory.
+func WithDADeduplication() Option {
+	return func(n *Node) { n.daDeduplication = true }
+}
+
+// WithStore makes the node persist blocks and block event index in kv, instead of keeping them in memory.
+//
+// Blocks (and index) stored in kv by previous runs are available after restart.
+func WithStore(kv store.KVStore) Option {
+	return func(n *Node) { n.kv = kv }
+}
+
+// WithTxGas sets the function returning gas wanted by transaction, used to verify that blocks respect MaxGas
+// consensus param (see VerifyChain). Blocks don't contain gas of transactions, so without it, MaxGas is not verified.
+func WithTxGas(txGas optimint.GasFunc) Option {
+	return func(n *Node) { n.txGas = txGas }
+}
+
+// WithTxRouter sets the function selecting gossip lane for transactions published from mempool.
+//
+// Lanes have to be configured in P2PConfig.TxLanes.
+func WithTxRouter(router p2p.TxRouter) Option {
+	return func(n *Node) { n.P2P.SetTxRouter(router) }
+}
+
+// WithMetrics sets the metrics.
+func WithMetrics(metrics *Metrics) Option {
+	return func(n *Node) { n.metrics = metrics }
+}
+
+// WithMempoolOptions sets optional parameters of the mempool (for example mempool.WithSequenceAdmission).
+//
+// Options are applied when the mempool is created, after options derived from configuration.
+func WithMempoolOptions(options ...mempool.CListMempoolOption) Option {
+	return func(n *Node) { n.mempoolOptions = append(n.mempoolOptions, options...) }
+}
+
+func NewNode(ctx context.Context, conf config.NodeConfig, nodeKey crypto.PrivKey, clientCreator proxy.ClientCreator, genesis *types.GenesisDoc, logger log.Logger, options ...Option) (node *Node, err error) {
+	if len(conf.Moniker) > config.MaxMonikerLength {
+		return nil, fmt.Errorf("%w: %d bytes (max: %d)", ErrMonikerTooLong, len(conf.Moniker), config.MaxMonikerLength)
+	}
+	switch conf.Mode {
+	case "":
+		conf.Mode = config.ModeFull
+	case config.ModeFull, config.ModeAggregator, config.ModeArchive:
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrInvalidMode, conf.Mode)
+	}
+	if conf.DAStartTimeout == 0 {
+		conf.DAStartTimeout = config.DefaultDAStartTimeout
+	}
+	if conf.DAStopTimeout == 0 {
+		conf.DAStopTimeout = config.DefaultDAStopTimeout
+	}
+	if conf.DAConfirmationDepth == 0 {
+		conf.DAConfirmationDepth = config.DefaultDAConfirmationDepth
+	}
+	if conf.DAPollInterval == 0 {
+		conf.DAPollInterval = config.DefaultDAPollInterval
+	}
+	if conf.DAPollInterval < 0 {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidDAPollInterval, conf.DAPollInterval)
+	}
+	if conf.DAVerifyTimeout == 0 {
+		conf.DAVerifyTimeout = config.DefaultDAVerifyTimeout
+	}
+	if conf.ShutdownTimeout == 0 {
+		conf.ShutdownTimeout = config.DefaultShutdownTimeout
+	}
+	if conf.CheckTxConcurrency <= 0 {
+		conf.CheckTxConcurrency = config.DefaultCheckTxConcurrency
+	}
+	if conf.CheckTxRetries == 0 {
+		conf.CheckTxRetries = config.DefaultCheckTxRetries
+	}
+	if conf.CheckTxRetryInterval == 0 {
+		conf.CheckTxRetryInterval = config.DefaultCheckTxRetryInterval
+	}
+	if conf.FeeEventKey == "" {
+		conf.FeeEventKey = config.DefaultFeeEventKey
+	}
+	if conf.LoopStallTimeout == 0 {
+		conf.LoopStallTimeout = config.DefaultLoopStallTimeout
+	}
+	if conf.TxBatchSize <= 0 {
+		conf.TxBatchSize = config.DefaultTxBatchSize
+	}
+	if conf.TxBatchInterval == 0 {
+		conf.TxBatchInterval = config.DefaultTxBatchInterval
+	}
+	if conf.TxGossipRetryInterval == 0 {
+		conf.TxGossipRetryInterval = config.DefaultTxGossipRetryInterval
+	}
+
+	// heights start at 1; genesis documents loaded from file are already normalized by Tendermint
+	if genesis.InitialHeight == 0 {
+		logger.Info("genesis initial height is 0, using 1")
+		genesis = copyGenesis(genesis)
+		genesis.InitialHeight = 1
+	}
+
+	if conf.NamespaceID == ([8]byte{}) {
+		conf.NamespaceID = optimint.ChainNamespaceID(genesis.ChainID)
+	}
+
 	proxyApp := proxy.NewAppConns(clientCreator)
 	proxyApp.SetLogger(logger.With("module", "proxy"))
-	if err := proxyApp.Start(); err != nil {
-		return nil, fmt.Errorf("error starting proxy app connections: %w", err)
+	if err := startProxyApp(ctx, proxyApp, conf.ProxyAppStartTimeout, logger); err != nil {
+		return nil, err
 	}
+	// started services are stopped if node can't be created, so they don't leak
+	defer func() {
+		if err != nil {
+			stopService(proxyApp, "proxy app", logger)
+		}
+	}()
 
 	eventBus := types.NewEventBus()
 	eventBus.SetLogger(logger.With("module", "events"))
 	if err := eventBus.Start(); err != nil {
 		return nil, err
 	}
+	defer func() {
+		if err != nil {
+			stopService(eventBus, "event bus", logger)
+		}
+	}()
 
+	genesisHash, err := conv.GenesisHash(genesis)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash genesis document: %w", err)
+	}
 	client, err := p2p.NewClient(conf.P2P, nodeKey, genesis.ChainID, logger.With("module", "p2p"))
 	if err != nil {
 		return nil, err
 	}
+	client.SetGenesisHash(genesisHash)
+	id, err := peer.IDFromPrivateKey(nodeKey)
+	if err != nil {
+		return nil, err
+	}
 
-	mp := mempool.NewCListMempool(llcfg.DefaultMempoolConfig(), proxyApp.Mempool(), 0)
+	var mpOptions []mempool.CListMempoolOption
+	if conf.MinGasPrice > 0 {
+		mpOptions = append(mpOptions, mempool.WithPostCheck(mempool.PostCheckMinGasPrice(conf.MinGasPrice, conf.FeeEventKey, conf.FeeDenom)))
+	}
+	if conf.CanonicalTxOrder {
+		mpOptions = append(mpOptions, mempool.WithCanonicalReapOrder())
+	}
+	if conf.TxEvictionEvents {
+		mpOptions = append(mpOptions, mempool.WithEvictionCallback(publishTxEvicted(eventBus, logger)))
+	}
 
-	node := &Node{
+	node = &Node{
 		proxyApp:     proxyApp,
 		eventBus:     eventBus,
 		genesis:      genesis,
+		genesisHash:  genesisHash,
 		conf:         conf,
+		id:           id,
 		P2P:          client,
-		Mempool:      mp,
 		mempoolIDs:   newMempoolIDs(),
 		incomingTxCh: make(chan *p2p.Tx),
+		retryTxCh:    make(chan *retryTx),
+		errors:       make(chan error, errorsCapacity),
 		BlockStore:   store.NewBlockStore(),
+		BlockIndexer: store.NewBlockIndexer(store.NewInMemoryKVStore(), conf.IndexBlockEvents),
 		ctx:          ctx,
+		loops:        make(map[string]chan struct{}),
+		watchdog:     newWatchdog(),
+		metrics:      NopMetrics(),
 	}
 	node.BaseService = *service.NewBaseService(logger, "Node", node)
+	for _, option := range options {
+		option(node)
+	}
+	mp := mempool.NewCListMempool(llcfg.DefaultMempoolConfig(), proxyApp.Mempool(), 0, append(mpOptions, node.mempoolOptions...)...)
+	node.Mempool = mp
+	if conf.CanonicalTxOrder && mp.SequenceAdmission() {
+		return nil, ErrIncompatibleTxOrder
+	}
+
+	if node.kv != nil {
+		blockStore, err := store.OpenBlockStore(node.kv, logger.With("module", "store"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to open block store: %w", err)
+		}
+		node.BlockStore = blockStore
+		node.BlockIndexer = store.NewBlockIndexer(node.kv, conf.IndexBlockEvents)
+	}
+	if node.dalc == nil && conf.DALayer != "" {
+		dalc := registry.GetClient(conf.DALayer)
+		if dalc == nil {
+			return nil, fmt.Errorf("%w: %q (registered: %v)", ErrUnknownDALayer, conf.DALayer, registry.RegisteredClients())
+		}
+		err := dalc.Init([]byte(conf.DAConfig), logger.With("module", "da_client"))
+		if err != nil {
+			return nil, fmt.Errorf("error while initializing DA layer client: %w", err)
+		}
+		node.dalc = dalc
+	}
+	if node.dalc != nil && conf.DAVerifySubmissions {
+		node.dalc = da.NewVerifyingClient(node.dalc, conf.DAConfirmationDepth, conf.DAPollInterval, conf.DAVerifyTimeout,
+			node.submissionVerified)
+	}
+	if node.dalc != nil && conf.DAAuditLog != "" {
+		auditLog, err := os.OpenFile(conf.DAAuditLog, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open DA audit log: %w", err)
+		}
+		node.auditLog = auditLog
+		node.dalc = da.NewAuditingClient(node.dalc, auditLog, logger.With("module", "da_audit"))
+	}
+	if node.dalc != nil && node.daDeduplication {
+		kv := node.kv
+		if kv == nil {
+			kv = store.NewInMemoryKVStore()
+		}
+		node.dalc = da.NewDeduplicatingClient(node.dalc, kv, logger.With("module", "da_dedup"))
+	}
 
 	return node, nil
 }
 
 func (n *Node) mempoolReadLoop(ctx context.Context) {
+	const name = "mempoolReadLoop"
+	defer n.watchdog.idle(name)
+
+	if n.conf.CheckTxConcurrency == 1 {
+		for {
+			n.watchdog.idle(name)
+			select {
+			case tx := <-n.incomingTxCh:
+				n.watchdog.active(name)
+				n.checkTx(ctx, tx, 0)
+			case r := <-n.retryTxCh:
+				n.watchdog.active(name)
+				n.checkTx(ctx, r.tx, r.attempt)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+
+	// each sender is assigned to a single worker, to preserve ordering of transactions from given peer
+	workers := make([]chan *retryTx, n.conf.CheckTxConcurrency)
+	var wg sync.WaitGroup
+	for i := range workers {
+		workers[i] = make(chan *retryTx)
+		wg.Add(1)
+		go func(txs <-chan *retryTx) {
+			defer wg.Done()
+			for r := range txs {
+				n.checkTx(ctx, r.tx, r.attempt)
+			}
+		}(workers[i])
+	}
+	defer func() {
+		for _, w := range workers {
+			close(w)
+		}
+		wg.Wait()
+	}()
+
+	dispatch := func(r *retryTx) bool {
+		h := fnv.New32a()
+		_, _ = h.Write([]byte(r.tx.From))
+		select {
+		case workers[h.Sum32()%uint32(len(workers))] <- r:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
 	for {
+		n.watchdog.idle(name)
 		select {
 		case tx := <-n.incomingTxCh:
-			n.Logger.Debug("tx received", "from", tx.From, "bytes", len(tx.Data))
-			err := n.Mempool.CheckTx(tx.Data, func(resp *abci.Response) {}, mempool.TxInfo{
-				SenderID:    n.mempoolIDs.GetForPeer(tx.From),
-				SenderP2PID: corep2p.ID(tx.From),
-				Context:     ctx,
-			})
-			if err != nil {
-				n.Logger.Error("failed to execute CheckTx", "error", err)
+			n.watchdog.active(name)
+			if !dispatch(&retryTx{tx: tx}) {
+				return
+			}
+		case r := <-n.retryTxCh:
+			n.watchdog.active(name)
+			if !dispatch(r) {
+				return
 			}
 		case <-ctx.Done():
 			return
@@ -98,35 +409,123 @@ func (n *Node) mempoolReadLoop(ctx context.Context) {
 	}
 }
 
+// txRejected logs and meters tx rejected by the application in CheckTx with response r.
+func (n *Node) txRejected(tx *p2p.Tx, r *abci.ResponseCheckTx) {
+	n.metrics.RejectedTxs.Add(1)
+	keyvals := []interface{}{"from", tx.From, "code", r.Code, "codespace", r.Codespace, "log", r.Log}
+	if n.conf.LogRejectedTxs {
+		n.Logger.Info("tx rejected by application", keyvals...)
+	} else {
+		n.Logger.Debug("tx rejected by application", keyvals...)
+	}
+}
+
+// retryTx is a transaction waiting for another CheckTx attempt.
+type retryTx struct {
+	tx      *p2p.Tx
+	attempt int
+}
+
+// checkTx passes tx to mempool. attempt is the number of previous attempts, that failed with a transient error.
+//
+// CheckTx failed with a transient error is retried, up to CheckTxRetries times, with exponential backoff starting at
+// CheckTxRetryInterval. Retried transaction is passed back to mempoolReadLoop, so it doesn't block processing of
+// subsequent transactions (including transactions from the same peer, which can be checked before the retry).
+func (n *Node) checkTx(ctx context.Context, tx *p2p.Tx, attempt int) {
+	if attempt == 0 {
+		n.Logger.Debug("tx received", "from", tx.From, "bytes", len(tx.Data))
+	}
+	// node context is used, so in-flight CheckTx is not aborted on shutdown
+	err := n.Mempool.CheckTx(tx.Data, func(resp *abci.Response) {
+		if r := resp.GetCheckTx(); r != nil && r.Code != abci.CodeTypeOK {
+			n.txRejected(tx, r)
+		}
+	}, mempool.TxInfo{
+		SenderID:    n.mempoolIDs.GetForPeer(tx.From),
+		SenderP2PID: corep2p.ID(tx.From),
+		Context:     n.ctx,
+		GossipTTL:   tx.TTL,
+	})
+	if err == nil {
+		return
+	}
+	if !isTransientCheckTxError(err) {
+		n.Logger.Debug("tx rejected by mempool", "from", tx.From, "error", err)
+		return
+	}
+	if attempt >= n.conf.CheckTxRetries {
+		n.Logger.Error("failed to execute CheckTx", "error", err, "attempts", attempt+1)
+		n.reportError(&OperationalError{Kind: ErrCheckTx, Detail: fmt.Sprintf("from %s, attempts %d", tx.From, attempt+1), Err: err})
+		return
+	}
+
+	backoff := n.conf.CheckTxRetryInterval << uint(attempt)
+	n.Logger.Debug("retrying CheckTx", "from", tx.From, "error", err, "attempt", attempt+1, "backoff", backoff)
+	time.AfterFunc(backoff, func() {
+		select {
+		case n.retryTxCh <- &retryTx{tx: tx, attempt: attempt + 1}:
+		case <-ctx.Done():
+		}
+	})
+}
+
 func (n *Node) mempoolPublishLoop(ctx context.Context) {
-	rawMempool := n.Mempool.(*mempool.CListMempool)
-	var next *clist.CElement
+	var (
+		next *clist.CElement
+		// batch holds transactions waiting to be gossiped, until TxBatchSize or TxBatchInterval is reached
+		batch []*mempool.MempoolTx
+		timer *time.Timer
+		// flush is nil when there is no pending batch
+		flush <-chan time.Time
+	)
+	const name = "mempoolPublishLoop"
+	defer n.watchdog.idle(name)
+	defer func() {
+		if timer != nil {
+			timer.Stop()
+		}
+	}()
+	gossip := func() bool {
+		if timer != nil {
+			timer.Stop()
+		}
+		flush = nil
+		ok := n.gossipTxs(ctx, batch)
+		batch = batch[:0]
+		return ok
+	}
 
 	for {
+		n.watchdog.active(name)
 		// wait for transactions
 		n.Logger.Debug("loop begin")
 		if next == nil {
 			n.Logger.Debug("waiting for mempool")
+			n.watchdog.idle(name)
 			select {
-			case <-rawMempool.TxsWaitChan():
-				if next = rawMempool.TxsFront(); next != nil {
+			case <-n.Mempool.TxsWaitChan():
+				if next = n.Mempool.TxsFront(); next != nil {
 					continue
 				}
+			case <-flush:
+				n.watchdog.active(name)
+				if !gossip() {
+					return
+				}
 			case <-ctx.Done():
 				return
 			}
+			continue
 		}
 
 		// send transactions
 		for {
-			n.Logger.Debug("Gossiping...")
 			memTx := next.Value.(*mempool.MempoolTx)
-			tx := memTx.Tx
-
-			err := n.P2P.GossipTx(ctx, tx)
-			if err != nil {
-				n.Logger.Error("failed to gossip transaction", "error", err)
-				continue
+			batch = append(batch, memTx)
+			if len(batch) >= n.conf.TxBatchSize {
+				if !gossip() {
+					return
+				}
 			}
 
 			nx := next.Next()
@@ -135,34 +534,234 @@ func (n *Node) mempoolPublishLoop(ctx context.Context) {
 			}
 			next = nx
 		}
+		if len(batch) > 0 && flush == nil {
+			timer = time.NewTimer(n.conf.TxBatchInterval)
+			flush = timer.C
+		}
 
 		n.Logger.Debug("waiting for next...")
+	wait:
+		for {
+			n.watchdog.idle(name)
+			select {
+			case <-next.NextWaitChan():
+				next = next.Next()
+				break wait
+			case <-flush:
+				n.watchdog.active(name)
+				if !gossip() {
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+}
+
+// gossipTxs gossips transactions to peers. Transactions not gossiped because there are no peers (see
+// P2PConfig.ConfirmGossip) are retried every TxGossipRetryInterval until they're gossiped. Transactions that fail to
+// be gossiped for other reasons are dropped from gossip (they stay in mempool).
+// Local transactions are gossiped with configured GossipTTL. Transactions received from peers are relayed with
+// decremented TTL, unless their TTL is already exhausted.
+// False is returned if ctx is done.
+func (n *Node) gossipTxs(ctx context.Context, memTxs []*mempool.MempoolTx) bool {
+	// transactions grouped by TTL they were received with; 0 is used for local transactions
+	byTTL := make(map[uint8][][]byte)
+	for _, memTx := range memTxs {
+		ttl := memTx.GossipTTL()
+		if ttl == 1 {
+			continue
+		}
+		byTTL[ttl] = append(byTTL[ttl], memTx.Tx)
+	}
+	ttls := make([]int, 0, len(byTTL))
+	for ttl := range byTTL {
+		ttls = append(ttls, int(ttl))
+	}
+	sort.Ints(ttls)
+
+	for _, ttl := range ttls {
+		if !n.gossipTxsWithTTL(ctx, byTTL[uint8(ttl)], uint8(ttl)) {
+			return false
+		}
+	}
+	return true
+}
+
+func (n *Node) gossipTxsWithTTL(ctx context.Context, txs [][]byte, receivedTTL uint8) bool {
+	for {
+		n.Logger.Debug("Gossiping...", "txs", len(txs), "receivedTTL", receivedTTL)
+		var err error
+		if receivedTTL == 0 {
+			err = n.P2P.GossipTxs(ctx, txs)
+		} else {
+			err = n.P2P.RelayTxs(ctx, txs, receivedTTL-1)
+		}
+		if err == nil {
+			return true
+		}
+		n.Logger.Error("failed to gossip transactions", "error", err)
+		n.reportError(&OperationalError{Kind: ErrGossipTxs, Detail: fmt.Sprintf("%d txs", len(txs)), Err: err})
+
+		if txs = n.retryableTxs(err, txs); len(txs) == 0 {
+			return true
+		}
 		select {
-		case <-next.NextWaitChan():
-			next = next.Next()
+		case <-time.After(n.conf.TxGossipRetryInterval):
 		case <-ctx.Done():
-			return
+			return false
 		}
 	}
 }
 
+// retryableTxs returns transactions that failed to be gossiped with err, and should be retried. Only gossip failed
+// because there are no peers is retried. Other errors are permanent (e.g. message too large, unknown lane), or
+// retrying them would only add to pubsub backpressure (timeout); such transactions are dropped from gossip.
+func (n *Node) retryableTxs(err error, txs [][]byte) [][]byte {
+	var gossipErr *p2p.TxGossipError
+	if !errors.As(err, &gossipErr) {
+		if errors.Is(err, p2p.ErrNoPeers) {
+			return txs
+		}
+		n.metrics.DroppedGossipTxs.Add(float64(len(txs)))
+		return nil
+	}
+
+	var retry [][]byte
+	for _, failed := range gossipErr.Failed {
+		if errors.Is(failed.Err, p2p.ErrNoPeers) {
+			retry = append(retry, failed.Txs...)
+		} else {
+			n.metrics.DroppedGossipTxs.Add(float64(len(failed.Txs)))
+		}
+	}
+	return retry
+}
+
 func (n *Node) OnStart() error {
+	n.Logger.Info("starting node", "moniker", n.conf.Moniker, "id", n.id.Pretty(), "mode", n.conf.Mode)
+
+	if n.dalc != nil {
+		n.Logger.Info("starting DA layer client")
+		err := runWithTimeout(n.ctx, n.conf.DAStartTimeout, n.dalc.Start)
+		if err != nil {
+			return fmt.Errorf("error while starting DA layer client: %w", err)
+		}
+	}
+
 	n.Logger.Info("starting P2P client")
 	err := n.P2P.Start(n.ctx)
 	if err != nil {
 		return fmt.Errorf("error while starting P2P client: %w", err)
 	}
-	go n.mempoolReadLoop(n.ctx)
-	go n.mempoolPublishLoop(n.ctx)
-	n.P2P.SetTxHandler(func(tx *p2p.Tx) {
-		n.incomingTxCh <- tx
-	})
 
+	var loopCtx context.Context
+	loopCtx, n.cancelLoops = context.WithCancel(n.ctx)
+	n.startLoop(loopCtx, "watchdogLoop", n.watchdogLoop)
+	// aggregators produce blocks, other nodes sync them from DA layer
+	if n.dalc != nil && n.conf.Mode != config.ModeAggregator {
+		n.startLoop(loopCtx, "daSyncLoop", n.daSyncLoop)
+	}
+	// archive nodes only serve blocks, so they don't accept transactions
+	if n.conf.Mode != config.ModeArchive {
+		n.startLoop(loopCtx, "mempoolReadLoop", n.mempoolReadLoop)
+		n.startLoop(loopCtx, "mempoolPublishLoop", n.mempoolPublishLoop)
+		n.P2P.SetTxHandler(func(tx *p2p.Tx) {
+			n.receiveTx(loopCtx, tx)
+		})
+	}
+
+	n.Logger.Info("node started", n.startupSummary()...)
 	return nil
 }
 
+// startupSummary returns key-value pairs describing effective configuration of started node.
+func (n *Node) startupSummary() []interface{} {
+	daLayer := n.conf.DALayer
+	if n.dalc == nil {
+		daLayer = "none"
+	} else if daLayer == "" {
+		daLayer = "custom"
+	}
+	startHeight := n.genesis.InitialHeight
+	if height := n.BlockStore.Height(); height > 0 {
+		startHeight = int64(height) + 1
+	}
+	return []interface{}{
+		"chainID", n.genesis.ChainID,
+		"mode", n.conf.Mode,
+		"aggregator", n.conf.Mode == config.ModeAggregator,
+		"daLayer", daLayer,
+		"listenAddrs", n.P2P.Addrs(),
+		"startHeight", startHeight,
+	}
+}
+
+// receiveTx passes valid transactions received from peers to mempoolReadLoop. Invalid transactions are dropped.
+func (n *Node) receiveTx(ctx context.Context, tx *p2p.Tx) {
+	if err := tx.ValidateBasic(); err != nil {
+		invalid := atomic.AddUint64(&n.invalidTxs, 1)
+		n.metrics.InvalidTxs.Add(1)
+		n.Logger.Debug("dropping invalid tx", "from", tx.From, "error", err, "total", invalid)
+		return
+	}
+	select {
+	case n.incomingTxCh <- tx:
+	case <-ctx.Done():
+	}
+}
+
+// startLoop runs loop in a separate goroutine. Loop is expected to return when ctx is done.
+func (n *Node) startLoop(ctx context.Context, name string, loop func(context.Context)) {
+	done := make(chan struct{})
+	n.loops[name] = done
+	go func() {
+		defer close(done)
+		loop(ctx)
+	}()
+}
+
+// stopLoops cancels all loops and waits for them to finish in-flight operations, up to ShutdownTimeout.
+func (n *Node) stopLoops() {
+	n.cancelLoops()
+
+	ctx, cancel := context.WithTimeout(context.Background(), n.conf.ShutdownTimeout)
+	defer cancel()
+
+	var abandoned []string
+	for name, done := range n.loops {
+		select {
+		case <-done:
+		case <-ctx.Done():
+			// loop could finish in the meantime
+			select {
+			case <-done:
+			default:
+				abandoned = append(abandoned, name)
+			}
+		}
+	}
+	if len(abandoned) > 0 {
+		sort.Strings(abandoned)
+		n.Logger.Error("shutdown timeout elapsed, abandoning in-flight operations", "loops", abandoned, "timeout", n.conf.ShutdownTimeout)
+	}
+}
+
 func (n *Node) OnStop() {
+	n.stopLoops()
+	if n.dalc != nil {
+		err := runWithTimeout(context.Background(), n.conf.DAStopTimeout, n.dalc.Stop)
+		if err != nil {
+			n.Logger.Error("error while stopping DA layer client", "error", err)
+		}
+	}
 	n.P2P.Close()
+	if n.auditLog != nil {
+		if err := n.auditLog.Close(); err != nil {
+			n.Logger.Error("error while closing DA audit log", "error", err)
+		}
+	}
 }
 
 func (n *Node) OnReset() error {
@@ -184,3 +783,210 @@ func (n *Node) EventBus() *types.EventBus {
 func (n *Node) ProxyApp() proxy.AppConns {
 	return n.proxyApp
 }
+
+// Errors returns channel of non-fatal operational errors (for example, failures of transaction gossiping), to allow
+// supervising code to react to them. Errors are reported in addition to being logged.
+//
+// Errors are *OperationalError, matching both the kind of failure (for example ErrGossipTxs) and its cause.
+// Channel is bounded; errors reported when it's full are dropped and counted (see DroppedErrors).
+func (n *Node) Errors() <-chan error {
+	return n.errors
+}
+
+// DroppedErrors returns number of errors not reported on Errors channel, because it was full.
+func (n *Node) DroppedErrors() uint64 {
+	return atomic.LoadUint64(&n.droppedErrors)
+}
+
+// reportError passes err to Errors channel, without blocking.
+func (n *Node) reportError(err error) {
+	select {
+	case n.errors <- err:
+	default:
+		atomic.AddUint64(&n.droppedErrors, 1)
+	}
+}
+
+// Info returns basic information identifying the node.
+func (n *Node) Info() corep2p.DefaultNodeInfo {
+	return corep2p.DefaultNodeInfo{
+		DefaultNodeID: corep2p.ID(n.id.Pretty()),
+		ListenAddr:    n.conf.P2P.ListenAddress,
+		Network:       n.genesis.ChainID,
+		Moniker:       n.conf.Moniker,
+	}
+}
+
+// WaitForHeight blocks until block store reaches given height, or ctx is done.
+//
+// It's driven by NewBlock events published on node's event bus (by DA sync). Blocks can be saved without NewBlock
+// event (for example, in aggregator mode, or by store.Import), so block store height is also checked every
+// DAPollInterval.
+func (n *Node) WaitForHeight(ctx context.Context, height uint64) error {
+	if n.BlockStore.Height() >= height {
+		return nil
+	}
+
+	subscriber := "WaitForHeight-" + tmrand.Str(8)
+	sub, err := n.eventBus.Subscribe(ctx, subscriber, types.EventQueryNewBlock, waitForHeightCapacity)
+	if err != nil {
+		return fmt.Errorf("failed to subscribe to NewBlock events: %w", err)
+	}
+	defer func() {
+		// subscription may be already cancelled
+		_ = n.eventBus.Unsubscribe(context.Background(), subscriber, types.EventQueryNewBlock)
+	}()
+
+	ticker := time.NewTicker(n.conf.DAPollInterval)
+	defer ticker.Stop()
+
+	// height is checked again after subscribing, so no block is missed
+	for n.BlockStore.Height() < height {
+		select {
+		case <-sub.Out():
+		case <-ticker.C:
+		case <-sub.Cancelled():
+			return fmt.Errorf("NewBlock subscription cancelled: %w", sub.Err())
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// submissionVerified logs and reports result of verification of block submitted to DA layer.
+func (n *Node) submissionVerified(block *optimint.Block, err error) {
+	if err == nil {
+		n.Logger.Debug("DA submission verified", "height", block.Header.Height)
+		return
+	}
+	n.Logger.Error("DA submission verification failed", "height", block.Header.Height, "error", err)
+	n.reportError(&OperationalError{Kind: ErrDAVerification, Detail: fmt.Sprintf("height %d", block.Header.Height), Err: err})
+}
+
+// WaitForDAFinality blocks until block submitted to DA layer has DAConfirmationDepth confirmations, or ctx is done.
+//
+// Block that is no longer included in DA layer (for example after DA layer reorg), or that doesn't appear in DA layer
+// before ctx is done, is reported with da.ErrBlockNotIncluded, and has to be submitted again.
+func (n *Node) WaitForDAFinality(ctx context.Context, block *optimint.Block) error {
+	if n.dalc == nil {
+		return ErrNoDALayer
+	}
+	return da.WaitForFinality(ctx, n.dalc, block, n.conf.DAConfirmationDepth, n.conf.DAPollInterval)
+}
+
+// Genesis returns a copy of the genesis document.
+//
+// Genesis document is re-hashed on every call, to detect modifications after startup, and validated with
+// ValidateAndComplete (on a separate copy, so returned document is not completed). ErrInvalidGenesis is returned
+// if genesis document was modified or is inconsistent.
+func (n *Node) Genesis() (*types.GenesisDoc, error) {
+	genesis := copyGenesis(n.genesis)
+	hash, err := conv.GenesisHash(genesis)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to hash genesis document: %v", ErrInvalidGenesis, err)
+	}
+	if hash != n.genesisHash {
+		return nil, fmt.Errorf("%w: genesis document was modified after startup", ErrInvalidGenesis)
+	}
+	for i, v := range genesis.Validators {
+		// ValidateAndComplete derives validator address from public key
+		if v.PubKey == nil {
+			return nil, fmt.Errorf("%w: validator %d has no public key", ErrInvalidGenesis, i)
+		}
+	}
+	if err := copyGenesis(genesis).ValidateAndComplete(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidGenesis, err)
+	}
+	return genesis, nil
+}
+
+// NamespaceID returns namespace ID of the chain, either configured or derived from chain ID.
+func (n *Node) NamespaceID() [8]byte {
+	return n.conf.NamespaceID
+}
+
+// NamespaceIDAt returns namespace ID used for block at given height, taking configured namespace epochs into account.
+// It's the namespace to submit the block to and to retrieve it from (see da.DataAvailabilityLayerClient).
+func (n *Node) NamespaceIDAt(height uint64) [8]byte {
+	return optimint.EpochNamespaceID(n.conf.NamespaceID, n.conf.NamespaceEpochLength, height)
+}
+
+// GenesisHash returns hash of the genesis document, computed at startup.
+func (n *Node) GenesisHash() [32]byte {
+	return n.genesisHash
+}
+
+// startProxyApp starts proxy app connections.
+//
+// If application is not available, start is retried with exponential backoff, until timeout elapses.
+func startProxyApp(ctx context.Context, proxyApp proxy.AppConns, timeout time.Duration, logger log.Logger) error {
+	deadline := time.Now().Add(timeout)
+	backoff := proxyAppMinBackoff
+	for {
+		err := proxyApp.Start()
+		if err == nil {
+			return nil
+		}
+		if time.Now().Add(backoff).After(deadline) {
+			return fmt.Errorf("error starting proxy app connections (timeout: %s): %w", timeout, err)
+		}
+
+		logger.Info("failed to start proxy app connections, retrying", "error", err, "backoff", backoff)
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("error starting proxy app connections: %w", ctx.Err())
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		if backoff > proxyAppMaxBackoff {
+			backoff = proxyAppMaxBackoff
+		}
+	}
+}
+
+// stopService stops service started by NewNode, logging failure.
+func stopService(s service.Service, name string, logger log.Logger) {
+	if err := s.Stop(); err != nil {
+		logger.Error("failed to stop "+name, "error", err)
+	}
+}
+
+// runWithTimeout calls fn with a context that expires after timeout.
+//
+// It returns when fn returns or the timeout elapses, whichever happens first, so fn that ignores the context
+// can't block the caller forever.
+func runWithTimeout(ctx context.Context, timeout time.Duration, fn func(context.Context) error) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- fn(ctx)
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// copyGenesis returns a deep copy of genesis document.
+func copyGenesis(genesis *types.GenesisDoc) *types.GenesisDoc {
+	genesisCopy := *genesis
+	if genesis.ConsensusParams != nil {
+		params := *genesis.ConsensusParams
+		params.Validator.PubKeyTypes = append(params.Validator.PubKeyTypes[:0:0], params.Validator.PubKeyTypes...)
+		genesisCopy.ConsensusParams = &params
+	}
+	if genesis.Validators != nil {
+		genesisCopy.Validators = make([]types.GenesisValidator, len(genesis.Validators))
+		copy(genesisCopy.Validators, genesis.Validators)
+	}
+	genesisCopy.AppHash = append(genesis.AppHash[:0:0], genesis.AppHash...)
+	genesisCopy.AppState = append(genesis.AppState[:0:0], genesis.AppState...)
+	return &genesisCopy
+}
@@ -1,16 +1,26 @@
 package node
 
 import (
+	"bytes"
 	"context"
 	"crypto/rand"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/go-kit/kit/metrics/generic"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
+	abcicli "github.com/lazyledger/lazyledger-core/abci/client"
 	abci "github.com/lazyledger/lazyledger-core/abci/types"
+	"github.com/lazyledger/lazyledger-core/crypto/ed25519"
 	"github.com/lazyledger/lazyledger-core/libs/log"
 	"github.com/lazyledger/lazyledger-core/proxy"
 	"github.com/lazyledger/lazyledger-core/types"
@@ -18,8 +28,13 @@ import (
 	"github.com/libp2p/go-libp2p-core/peer"
 
 	"github.com/lazyledger/optimint/config"
+	"github.com/lazyledger/optimint/da"
+	damock "github.com/lazyledger/optimint/da/mock"
+	"github.com/lazyledger/optimint/mempool"
 	"github.com/lazyledger/optimint/mocks"
 	"github.com/lazyledger/optimint/p2p"
+	"github.com/lazyledger/optimint/store"
+	optimint "github.com/lazyledger/optimint/types"
 )
 
 // simply check that node is starting and stopping without panicking
@@ -72,3 +87,1204 @@ func TestMempoolDirectly(t *testing.T) {
 
 	assert.Equal(int64(4*len("tx*")), node.Mempool.TxsBytes())
 }
+
+// blockingDA is a DA layer client that never finishes starting, ignoring the context.
+type blockingDA struct {
+	damock.MockDataAvailabilityLayerClient
+	unblock chan struct{}
+}
+
+func (b *blockingDA) Start(ctx context.Context) error {
+	<-b.unblock
+	return nil
+}
+
+func TestDAStartTimeout(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	app := &mocks.Application{}
+	key, _, _ := crypto.GenerateEd25519Key(rand.Reader)
+	conf := config.NodeConfig{DAStartTimeout: 100 * time.Millisecond}
+	node, err := NewNode(context.Background(), conf, key, proxy.NewLocalClientCreator(app), &types.GenesisDoc{}, log.TestingLogger())
+	require.NoError(err)
+	require.NotNil(node)
+
+	dalc := &blockingDA{unblock: make(chan struct{})}
+	defer close(dalc.unblock)
+	require.NoError(dalc.Init(nil, node.Logger))
+	WithDALayerClient(dalc)(node)
+
+	start := time.Now()
+	err = node.Start()
+	assert.ErrorIs(err, context.DeadlineExceeded)
+	assert.Less(time.Since(start), time.Second)
+	assert.False(node.IsRunning())
+}
+
+// delayedClientCreator fails to create ABCI client until available time.
+type delayedClientCreator struct {
+	proxy.ClientCreator
+	available time.Time
+}
+
+func (d *delayedClientCreator) NewABCIClient() (abcicli.Client, error) {
+	if time.Now().Before(d.available) {
+		return nil, errors.New("application not available yet")
+	}
+	return d.ClientCreator.NewABCIClient()
+}
+
+// recordingClientCreator records created ABCI clients.
+type recordingClientCreator struct {
+	proxy.ClientCreator
+	clients []abcicli.Client
+}
+
+func (r *recordingClientCreator) NewABCIClient() (abcicli.Client, error) {
+	client, err := r.ClientCreator.NewABCIClient()
+	if err == nil {
+		r.clients = append(r.clients, client)
+	}
+	return client, err
+}
+
+func TestNewNodeErrorStopsServices(t *testing.T) {
+	seqFn := func(types.Tx, *abci.ResponseCheckTx) (string, uint64, uint64, bool) { return "", 0, 0, false }
+	cases := []struct {
+		name        string
+		conf        config.NodeConfig
+		options     []Option
+		expectedErr error
+	}{
+		{"incompatible tx order", config.NodeConfig{CanonicalTxOrder: true},
+			[]Option{WithMempoolOptions(mempool.WithSequenceAdmission(seqFn, 0))}, ErrIncompatibleTxOrder},
+		{"unknown DA layer", config.NodeConfig{DALayer: "unknown"}, nil, ErrUnknownDALayer},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			creator := &recordingClientCreator{ClientCreator: proxy.NewLocalClientCreator(&mocks.Application{})}
+			node, err := NewNode(context.Background(), c.conf, getKey(t), creator, &types.GenesisDoc{}, log.TestingLogger(), c.options...)
+			assert.ErrorIs(err, c.expectedErr)
+			assert.Nil(node)
+
+			assert.NotEmpty(creator.clients)
+			for _, client := range creator.clients {
+				assert.False(client.IsRunning())
+			}
+		})
+	}
+}
+
+func TestProxyAppRetry(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name    string
+		delay   time.Duration
+		timeout time.Duration
+		err     bool
+	}{
+		{"no delay, no retries", 0, 0, false},
+		{"delay, no retries", 300 * time.Millisecond, 0, true},
+		{"delay, enough retries", 300 * time.Millisecond, 3 * time.Second, false},
+		{"delay, not enough retries", 3 * time.Second, 300 * time.Millisecond, true},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+			assert := assert.New(t)
+
+			app := &mocks.Application{}
+			key, _, _ := crypto.GenerateEd25519Key(rand.Reader)
+			conf := config.NodeConfig{ProxyAppStartTimeout: c.timeout}
+			creator := &delayedClientCreator{
+				ClientCreator: proxy.NewLocalClientCreator(app),
+				available:     time.Now().Add(c.delay),
+			}
+			node, err := NewNode(context.Background(), conf, key, creator, &types.GenesisDoc{}, log.TestingLogger())
+			if c.err {
+				assert.Error(err)
+				assert.Nil(node)
+			} else {
+				assert.NoError(err)
+				assert.NotNil(node)
+				assert.True(node.ProxyApp().IsRunning())
+			}
+		})
+	}
+}
+
+// startStopDA is a DA layer client that records calls to Start and Stop.
+type startStopDA struct {
+	damock.MockDataAvailabilityLayerClient
+	started, stopped bool
+}
+
+func (s *startStopDA) Start(ctx context.Context) error {
+	s.started = true
+	return s.MockDataAvailabilityLayerClient.Start(ctx)
+}
+
+func (s *startStopDA) Stop(ctx context.Context) error {
+	s.stopped = true
+	return s.MockDataAvailabilityLayerClient.Stop(ctx)
+}
+
+func TestCustomDALayerClient(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	dalc := &startStopDA{}
+	require.NoError(dalc.Init(nil, log.TestingLogger()))
+
+	app := &mocks.Application{}
+	key, _, _ := crypto.GenerateEd25519Key(rand.Reader)
+	node, err := NewNode(context.Background(), config.NodeConfig{}, key, proxy.NewLocalClientCreator(app), &types.GenesisDoc{}, log.TestingLogger(), WithDALayerClient(dalc))
+	require.NoError(err)
+	require.NotNil(node)
+
+	assert.Same(dalc, node.dalc)
+
+	require.NoError(node.Start())
+	assert.True(dalc.started)
+	assert.False(dalc.stopped)
+
+	require.NoError(node.Stop())
+	assert.True(dalc.stopped)
+}
+
+func TestWithStore(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	kv := store.NewInMemoryKVStore()
+	conf := config.NodeConfig{IndexBlockEvents: []string{"upgrade.name"}}
+	newNode := func() *Node {
+		node, err := NewNode(context.Background(), conf, getKey(t), proxy.NewLocalClientCreator(&mocks.Application{}), &types.GenesisDoc{}, log.TestingLogger(), WithStore(kv))
+		require.NoError(err)
+		return node
+	}
+
+	node := newNode()
+	require.NoError(node.BlockStore.SaveBlock(&optimint.Block{Header: optimint.Header{Height: 1}}))
+	require.NoError(node.BlockIndexer.Index(1, []abci.Event{{
+		Type:       "upgrade",
+		Attributes: []abci.EventAttribute{{Key: []byte("name"), Value: []byte("v2")}},
+	}}))
+
+	// blocks and index are available after restart
+	restarted := newNode()
+	assert.Equal(uint64(1), restarted.BlockStore.Height())
+	heights, err := restarted.BlockIndexer.Search("upgrade.name", "v2")
+	assert.NoError(err)
+	assert.Equal([]uint64{1}, heights)
+}
+
+func TestDADeduplicationStore(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	kv := store.NewInMemoryKVStore()
+	dalc := &damock.MockDataAvailabilityLayerClient{}
+	require.NoError(dalc.Init(nil, log.TestingLogger()))
+	node, err := NewNode(context.Background(), config.NodeConfig{}, getKey(t), proxy.NewLocalClientCreator(&mocks.Application{}), &types.GenesisDoc{}, log.TestingLogger(),
+		WithDALayerClient(dalc), WithStore(kv), WithDADeduplication())
+	require.NoError(err)
+	require.IsType(&da.DeduplicatingClient{}, node.dalc)
+
+	// DA height of submitted block is saved in node store
+	require.Equal(da.StatusSuccess, node.dalc.SubmitBlock(&optimint.Block{Header: optimint.Header{Height: 1}}).Code)
+	it := kv.PrefixIterator([]byte{9})
+	defer it.Discard()
+	assert.True(it.Valid())
+}
+
+func TestDALayerFromRegistry(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	app := &mocks.Application{}
+	key, _, _ := crypto.GenerateEd25519Key(rand.Reader)
+
+	node, err := NewNode(context.Background(), config.NodeConfig{DALayer: "mock"}, key, proxy.NewLocalClientCreator(app), &types.GenesisDoc{}, log.TestingLogger())
+	require.NoError(err)
+	require.NotNil(node)
+	assert.IsType(&damock.MockDataAvailabilityLayerClient{}, node.dalc)
+
+	node, err = NewNode(context.Background(), config.NodeConfig{DALayer: "nonexistent"}, key, proxy.NewLocalClientCreator(app), &types.GenesisDoc{}, log.TestingLogger())
+	assert.ErrorIs(err, ErrUnknownDALayer)
+	assert.Nil(node)
+
+	custom := &startStopDA{}
+	node, err = NewNode(context.Background(), config.NodeConfig{DALayer: "nonexistent"}, key, proxy.NewLocalClientCreator(app), &types.GenesisDoc{}, log.TestingLogger(), WithDALayerClient(custom))
+	require.NoError(err)
+	assert.Same(custom, node.dalc)
+}
+
+func TestMoniker(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	app := &mocks.Application{}
+	key, _, _ := crypto.GenerateEd25519Key(rand.Reader)
+	id, err := peer.IDFromPrivateKey(key)
+	require.NoError(err)
+	genesis := &types.GenesisDoc{ChainID: "test-chain"}
+
+	node, err := NewNode(context.Background(), config.NodeConfig{Moniker: "node-1"}, key, proxy.NewLocalClientCreator(app), genesis, log.TestingLogger())
+	require.NoError(err)
+	require.NotNil(node)
+
+	info := node.Info()
+	assert.Equal("node-1", info.Moniker)
+	assert.Equal("test-chain", info.Network)
+	assert.EqualValues(id.Pretty(), info.ID())
+
+	tooLong := strings.Repeat("x", config.MaxMonikerLength+1)
+	node, err = NewNode(context.Background(), config.NodeConfig{Moniker: tooLong}, key, proxy.NewLocalClientCreator(app), genesis, log.TestingLogger())
+	assert.ErrorIs(err, ErrMonikerTooLong)
+	assert.Nil(node)
+}
+
+func TestCanonicalTxOrderWithSequenceAdmission(t *testing.T) {
+	seqFn := func(types.Tx, *abci.ResponseCheckTx) (string, uint64, uint64, bool) { return "", 0, 0, false }
+	newNode := func(canonical bool, options ...Option) error {
+		_, err := NewNode(context.Background(), config.NodeConfig{CanonicalTxOrder: canonical}, getKey(t), proxy.NewLocalClientCreator(&mocks.Application{}), &types.GenesisDoc{}, log.TestingLogger(), options...)
+		return err
+	}
+
+	assert.NoError(t, newNode(true))
+	assert.NoError(t, newNode(false, WithMempoolOptions(mempool.WithSequenceAdmission(seqFn, 0))))
+	assert.ErrorIs(t, newNode(true, WithMempoolOptions(mempool.WithSequenceAdmission(seqFn, 0))), ErrIncompatibleTxOrder)
+
+	// options are applied when mempool is created, so they don't depend on mempool implementation
+	assert.NotPanics(t, func() {
+		WithMempoolOptions(mempool.WithSequenceAdmission(seqFn, 0))(&Node{})
+	})
+}
+
+func TestShutdownTimeout(t *testing.T) {
+	cases := []struct {
+		name      string
+		timeout   time.Duration
+		processed bool
+	}{
+		{"in-flight CheckTx completed", 2 * time.Second, true},
+		{"in-flight CheckTx abandoned", 50 * time.Millisecond, false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			processed := make(chan struct{})
+			app := &mocks.Application{}
+			app.On("CheckTx", mock.Anything).After(500 * time.Millisecond).Return(abci.ResponseCheckTx{}).Run(func(mock.Arguments) {
+				close(processed)
+			})
+			key, _, _ := crypto.GenerateEd25519Key(rand.Reader)
+			anotherKey, _, _ := crypto.GenerateEd25519Key(rand.Reader)
+			pid, err := peer.IDFromPrivateKey(anotherKey)
+			require.NoError(err)
+
+			node, err := NewNode(context.Background(), config.NodeConfig{ShutdownTimeout: c.timeout}, key, proxy.NewLocalClientCreator(app), &types.GenesisDoc{}, log.TestingLogger())
+			require.NoError(err)
+			require.NoError(node.Start())
+
+			node.incomingTxCh <- &p2p.Tx{Data: []byte("slow tx"), From: pid}
+			// give some time to start processing
+			time.Sleep(50 * time.Millisecond)
+
+			require.NoError(node.Stop())
+			select {
+			case <-processed:
+				assert.True(c.processed, "node should not wait for CheckTx")
+			default:
+				assert.False(c.processed, "node should wait for CheckTx")
+			}
+			<-processed
+		})
+	}
+}
+
+func TestCheckTxOrdering(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	const nSenders, nTxs = 4, 50
+
+	node, err := NewNode(context.Background(), config.NodeConfig{CheckTxConcurrency: 8}, getKey(t), proxy.NewLocalClientCreator(&mocks.Application{}), &types.GenesisDoc{}, log.TestingLogger())
+	require.NoError(err)
+	mp := &recordingMempool{delay: time.Millisecond, received: make(map[uint16][]types.Tx)}
+	mp.wg.Add(nSenders * nTxs)
+	node.Mempool = mp
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go node.mempoolReadLoop(ctx)
+
+	senders := make([]peer.ID, nSenders)
+	for i := range senders {
+		senders[i] = getPeerID(t)
+	}
+	for i := 0; i < nTxs; i++ {
+		for _, s := range senders {
+			node.incomingTxCh <- &p2p.Tx{Data: []byte{byte(i)}, From: s}
+		}
+	}
+	mp.wg.Wait()
+
+	for _, s := range senders {
+		txs := mp.received[node.mempoolIDs.GetForPeer(s)]
+		require.Len(txs, nTxs)
+		for i, tx := range txs {
+			assert.Equal(types.Tx{byte(i)}, tx)
+		}
+	}
+}
+
+func TestCheckTxRetries(t *testing.T) {
+	errAppBusy := errors.New("app busy")
+	cases := []struct {
+		name             string
+		errs             []error
+		expectedAttempts int
+		expectedAdded    bool
+	}{
+		{"success", nil, 1, true},
+		{"transient failure", []error{errAppBusy}, 2, true},
+		{"mempool full", []error{mempool.ErrMempoolIsFull{}, mempool.ErrMempoolIsFull{}}, 3, true},
+		{"retries exhausted", []error{errAppBusy, errAppBusy, errAppBusy}, 3, false},
+		{"tx in cache", []error{mempool.ErrTxInCache}, 1, false},
+		{"tx too large", []error{mempool.ErrTxTooLarge{}}, 1, false},
+		{"precheck failure", []error{mempool.ErrPreCheck{Reason: errors.New("invalid")}}, 1, false},
+		{"permanent after transient", []error{errAppBusy, mempool.ErrTxInCache}, 2, false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			conf := config.NodeConfig{CheckTxRetries: 2, CheckTxRetryInterval: time.Millisecond}
+			node, err := NewNode(context.Background(), conf, getKey(t), proxy.NewLocalClientCreator(&mocks.Application{}), &types.GenesisDoc{}, log.TestingLogger())
+			require.NoError(err)
+			mp := &failingMempool{errs: c.errs}
+			node.Mempool = mp
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			go node.mempoolReadLoop(ctx)
+
+			node.incomingTxCh <- &p2p.Tx{Data: []byte{1, 2, 3}, From: getPeerID(t)}
+			require.Eventually(func() bool {
+				return mp.getAttempts() >= c.expectedAttempts
+			}, time.Second, time.Millisecond)
+			// give unexpected retries a chance to happen
+			time.Sleep(20 * time.Millisecond)
+			assert.Equal(c.expectedAttempts, mp.getAttempts())
+			assert.Equal(c.expectedAdded, mp.isAdded())
+		})
+	}
+}
+
+func TestCheckTxRetryDoesNotBlock(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	conf := config.NodeConfig{CheckTxRetries: 1, CheckTxRetryInterval: 50 * time.Millisecond}
+	node, err := NewNode(context.Background(), conf, getKey(t), proxy.NewLocalClientCreator(&mocks.Application{}), &types.GenesisDoc{}, log.TestingLogger())
+	require.NoError(err)
+	mp := &failingMempool{errs: []error{errors.New("app busy")}}
+	node.Mempool = mp
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go node.mempoolReadLoop(ctx)
+
+	from := getPeerID(t)
+	node.incomingTxCh <- &p2p.Tx{Data: []byte{1}, From: from}
+	// second transaction is checked while the first one waits for retry
+	node.incomingTxCh <- &p2p.Tx{Data: []byte{2}, From: from}
+	require.Eventually(func() bool {
+		return mp.getAttempts() == 3
+	}, time.Second, time.Millisecond)
+	assert.Equal([]types.Tx{{2}, {1}}, mp.getAddedTxs())
+}
+
+func BenchmarkCheckTx(b *testing.B) {
+	const nSenders = 16
+
+	for _, concurrency := range []int{1, 4, 16} {
+		b.Run(fmt.Sprintf("concurrency=%d", concurrency), func(b *testing.B) {
+			node, err := NewNode(context.Background(), config.NodeConfig{CheckTxConcurrency: concurrency}, getKey(b), proxy.NewLocalClientCreator(&mocks.Application{}), &types.GenesisDoc{}, log.NewNopLogger())
+			require.NoError(b, err)
+			mp := &recordingMempool{delay: 100 * time.Microsecond, received: make(map[uint16][]types.Tx)}
+			node.Mempool = mp
+
+			senders := make([]peer.ID, nSenders)
+			for i := range senders {
+				senders[i] = getPeerID(b)
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			go node.mempoolReadLoop(ctx)
+
+			mp.wg.Add(b.N)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				node.incomingTxCh <- &p2p.Tx{Data: []byte{byte(i)}, From: senders[i%nSenders]}
+			}
+			mp.wg.Wait()
+		})
+	}
+}
+
+// recordingMempool simulates slow CheckTx and records transactions per sender. Only CheckTx is implemented.
+type recordingMempool struct {
+	mempool.Mempool
+
+	delay time.Duration
+	wg    sync.WaitGroup
+
+	mtx      sync.Mutex
+	received map[uint16][]types.Tx
+}
+
+func (m *recordingMempool) CheckTx(tx types.Tx, _ func(*abci.Response), txInfo mempool.TxInfo) error {
+	time.Sleep(m.delay)
+	m.mtx.Lock()
+	m.received[txInfo.SenderID] = append(m.received[txInfo.SenderID], tx)
+	m.mtx.Unlock()
+	m.wg.Done()
+	return nil
+}
+
+// failingMempool returns consecutive errors from errs for CheckTx calls. Only CheckTx is implemented.
+type failingMempool struct {
+	mempool.Mempool
+
+	errs []error
+
+	mtx      sync.Mutex
+	attempts int
+	added    []types.Tx
+}
+
+func (m *failingMempool) CheckTx(tx types.Tx, _ func(*abci.Response), _ mempool.TxInfo) error {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	m.attempts++
+	if m.attempts <= len(m.errs) {
+		return m.errs[m.attempts-1]
+	}
+	m.added = append(m.added, tx)
+	return nil
+}
+
+func (m *failingMempool) getAttempts() int {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	return m.attempts
+}
+
+func (m *failingMempool) isAdded() bool {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	return len(m.added) > 0
+}
+
+func (m *failingMempool) getAddedTxs() []types.Tx {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	return m.added
+}
+
+func getKey(t testing.TB) crypto.PrivKey {
+	key, _, err := crypto.GenerateEd25519Key(rand.Reader)
+	require.NoError(t, err)
+	return key
+}
+
+func getPeerID(t testing.TB) peer.ID {
+	pid, err := peer.IDFromPrivateKey(getKey(t))
+	require.NoError(t, err)
+	return pid
+}
+
+func TestReceiveInvalidTx(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	invalidTxs := generic.NewCounter("invalid_txs")
+	metrics := NopMetrics()
+	metrics.InvalidTxs = invalidTxs
+	node, err := NewNode(context.Background(), config.NodeConfig{}, getKey(t), proxy.NewLocalClientCreator(&mocks.Application{}), &types.GenesisDoc{}, log.TestingLogger(), WithMetrics(metrics))
+	require.NoError(err)
+	// buffered, so valid txs don't block without mempoolReadLoop
+	node.incomingTxCh = make(chan *p2p.Tx, 10)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	pid := getPeerID(t)
+	node.receiveTx(ctx, &p2p.Tx{Data: []byte{}, From: pid})
+	node.receiveTx(ctx, &p2p.Tx{Data: []byte("tx1")})
+	node.receiveTx(ctx, &p2p.Tx{Data: []byte("tx2"), From: pid})
+
+	assert.Equal(uint64(2), atomic.LoadUint64(&node.invalidTxs))
+	assert.Equal(float64(2), invalidTxs.Value())
+	require.Len(node.incomingTxCh, 1)
+	assert.Equal([]byte("tx2"), (<-node.incomingTxCh).Data)
+}
+
+func TestRejectedTxs(t *testing.T) {
+	for _, logRejected := range []bool{false, true} {
+		t.Run(fmt.Sprint(logRejected), func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			app := &mocks.Application{}
+			app.On("CheckTx", abci.RequestCheckTx{Tx: []byte("valid")}).Return(abci.ResponseCheckTx{})
+			app.On("CheckTx", mock.Anything).Return(abci.ResponseCheckTx{Code: 7, Codespace: "test", Log: "invalid tx"})
+			var buf bytes.Buffer
+			logger := log.NewFilter(log.NewTMLogger(&buf), log.AllowInfo())
+			rejectedTxs := generic.NewCounter("rejected_txs")
+			metrics := NopMetrics()
+			metrics.RejectedTxs = rejectedTxs
+			node, err := NewNode(context.Background(), config.NodeConfig{LogRejectedTxs: logRejected}, getKey(t),
+				proxy.NewLocalClientCreator(app), &types.GenesisDoc{}, logger,
+				WithMetrics(metrics))
+			require.NoError(err)
+
+			pid := getPeerID(t)
+			node.checkTx(context.Background(), &p2p.Tx{Data: []byte("valid"), From: pid}, 0)
+			node.checkTx(context.Background(), &p2p.Tx{Data: []byte("invalid1"), From: pid}, 0)
+			node.checkTx(context.Background(), &p2p.Tx{Data: []byte("invalid2"), From: pid}, 0)
+
+			assert.Equal(1, node.Mempool.Size())
+			assert.Equal(float64(2), rejectedTxs.Value())
+			if logRejected {
+				assert.Equal(2, strings.Count(buf.String(), "tx rejected by application"))
+				assert.Contains(buf.String(), "codespace=test")
+			} else {
+				assert.NotContains(buf.String(), "tx rejected by application")
+			}
+		})
+	}
+}
+
+func TestWaitForHeight(t *testing.T) {
+	require := require.New(t)
+
+	node, err := NewNode(context.Background(), config.NodeConfig{}, getKey(t), proxy.NewLocalClientCreator(&mocks.Application{}), &types.GenesisDoc{}, log.TestingLogger())
+	require.NoError(err)
+
+	// produce blocks in the background
+	go func() {
+		for h := uint64(1); h <= 5; h++ {
+			time.Sleep(10 * time.Millisecond)
+			assert.NoError(t, node.BlockStore.SaveBlock(&optimint.Block{Header: optimint.Header{Height: h}}))
+			assert.NoError(t, node.EventBus().PublishEventNewBlock(types.EventDataNewBlock{}))
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(node.WaitForHeight(ctx, 5))
+	require.Equal(uint64(5), node.BlockStore.Height())
+
+	// height already reached
+	require.NoError(node.WaitForHeight(ctx, 3))
+
+	// height is never reached
+	ctx, cancel = context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	require.ErrorIs(node.WaitForHeight(ctx, 6), context.DeadlineExceeded)
+}
+
+func TestWaitForHeightWithoutEvents(t *testing.T) {
+	for _, mode := range []string{config.ModeAggregator, config.ModeArchive} {
+		t.Run(mode, func(t *testing.T) {
+			require := require.New(t)
+
+			conf := config.NodeConfig{Mode: mode, DAPollInterval: 10 * time.Millisecond}
+			node, err := NewNode(context.Background(), conf, getKey(t), proxy.NewLocalClientCreator(&mocks.Application{}), &types.GenesisDoc{}, log.TestingLogger())
+			require.NoError(err)
+			require.NoError(node.Start())
+			defer func() {
+				require.NoError(node.Stop())
+			}()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			// height already reached
+			for h := uint64(1); h <= 3; h++ {
+				require.NoError(node.BlockStore.SaveBlock(&optimint.Block{Header: optimint.Header{Height: h}}))
+			}
+			require.NoError(node.WaitForHeight(ctx, 3))
+
+			// blocks saved without NewBlock events
+			go func() {
+				for h := uint64(4); h <= 5; h++ {
+					time.Sleep(10 * time.Millisecond)
+					assert.NoError(t, node.BlockStore.SaveBlock(&optimint.Block{Header: optimint.Header{Height: h}}))
+				}
+			}()
+			require.NoError(node.WaitForHeight(ctx, 5))
+		})
+	}
+}
+
+func TestGenesisMismatch(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	app := &mocks.Application{}
+	app.On("CheckTx", mock.Anything).Return(abci.ResponseCheckTx{})
+	key1 := getKey(t)
+	id1, err := peer.IDFromPrivateKey(key1)
+	require.NoError(err)
+
+	// same chain ID, different genesis
+	node1, err := NewNode(context.Background(), config.NodeConfig{
+		P2P: config.P2PConfig{
+			ListenAddress: "/ip4/127.0.0.1/tcp/9011",
+		},
+	}, key1, proxy.NewLocalClientCreator(app), &types.GenesisDoc{ChainID: "test", AppState: []byte(`{"a":1}`)}, log.TestingLogger())
+	require.NoError(err)
+	node2, err := NewNode(context.Background(), config.NodeConfig{
+		P2P: config.P2PConfig{
+			ListenAddress: "/ip4/127.0.0.1/tcp/9012",
+			Seeds:         "/ip4/127.0.0.1/tcp/9011/p2p/" + id1.Pretty(),
+		},
+	}, getKey(t), proxy.NewLocalClientCreator(app), &types.GenesisDoc{ChainID: "test", AppState: []byte(`{"a":2}`)}, log.TestingLogger())
+	require.NoError(err)
+	assert.NotEqual(node1.GenesisHash(), node2.GenesisHash())
+
+	require.NoError(node1.Start())
+	defer func() { assert.NoError(node1.Stop()) }()
+	require.NoError(node2.Start())
+	defer func() { assert.NoError(node2.Stop()) }()
+
+	// node2 dials node1 as a seed; node1 rejects it during handshake and refuses further connections
+	require.Eventually(func() bool {
+		return node1.P2P.RejectedPeers() == 1
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestTxBatchGossiping(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	app := &mocks.Application{}
+	app.On("CheckTx", mock.Anything).Return(abci.ResponseCheckTx{})
+	key1 := getKey(t)
+	id1, err := peer.IDFromPrivateKey(key1)
+	require.NoError(err)
+	genesis := &types.GenesisDoc{ChainID: "test"}
+
+	node1, err := NewNode(context.Background(), config.NodeConfig{
+		P2P: config.P2PConfig{
+			ListenAddress: "/ip4/127.0.0.1/tcp/9013",
+		},
+		TxBatchSize:     5,
+		TxBatchInterval: 100 * time.Millisecond,
+	}, key1, proxy.NewLocalClientCreator(app), genesis, log.TestingLogger())
+	require.NoError(err)
+	node2, err := NewNode(context.Background(), config.NodeConfig{
+		P2P: config.P2PConfig{
+			ListenAddress: "/ip4/127.0.0.1/tcp/9014",
+			Seeds:         "/ip4/127.0.0.1/tcp/9013/p2p/" + id1.Pretty(),
+		},
+	}, getKey(t), proxy.NewLocalClientCreator(app), genesis, log.TestingLogger())
+	require.NoError(err)
+
+	require.NoError(node1.Start())
+	defer func() { assert.NoError(node1.Stop()) }()
+	require.NoError(node2.Start())
+	defer func() { assert.NoError(node2.Stop()) }()
+
+	time.Sleep(2 * time.Second)
+
+	// 2 full batches, and 2 transactions gossiped after TxBatchInterval
+	const txs = 12
+	for i := 0; i < txs; i++ {
+		require.NoError(node1.Mempool.CheckTx([]byte(fmt.Sprintf("tx%d", i)), nil, mempool.TxInfo{}))
+	}
+
+	assert.Eventually(func() bool {
+		return node2.Mempool.Size() == txs
+	}, 3*time.Second, 50*time.Millisecond)
+}
+
+func TestConfirmGossip(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	app := &mocks.Application{}
+	app.On("CheckTx", mock.Anything).Return(abci.ResponseCheckTx{})
+	key1 := getKey(t)
+	id1, err := peer.IDFromPrivateKey(key1)
+	require.NoError(err)
+	genesis := &types.GenesisDoc{ChainID: "test"}
+
+	node1, err := NewNode(context.Background(), config.NodeConfig{
+		P2P: config.P2PConfig{
+			ListenAddress: "/ip4/127.0.0.1/tcp/9015",
+			ConfirmGossip: true,
+		},
+		TxGossipRetryInterval: 50 * time.Millisecond,
+	}, key1, proxy.NewLocalClientCreator(app), genesis, log.TestingLogger())
+	require.NoError(err)
+	require.NoError(node1.Start())
+	defer func() { assert.NoError(node1.Stop()) }()
+
+	// there are no peers, so gossip is retried until second node connects
+	require.NoError(node1.Mempool.CheckTx([]byte("tx"), nil, mempool.TxInfo{}))
+	time.Sleep(500 * time.Millisecond)
+
+	node2, err := NewNode(context.Background(), config.NodeConfig{
+		P2P: config.P2PConfig{
+			ListenAddress: "/ip4/127.0.0.1/tcp/9016",
+			Seeds:         "/ip4/127.0.0.1/tcp/9015/p2p/" + id1.Pretty(),
+		},
+	}, getKey(t), proxy.NewLocalClientCreator(app), genesis, log.TestingLogger())
+	require.NoError(err)
+	require.NoError(node2.Start())
+	defer func() { assert.NoError(node2.Stop()) }()
+
+	assert.Eventually(func() bool {
+		return node2.Mempool.Size() == 1
+	}, 5*time.Second, 50*time.Millisecond)
+}
+
+func TestGossipTTL(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	app := &mocks.Application{}
+	app.On("CheckTx", mock.Anything).Return(abci.ResponseCheckTx{})
+	key1 := getKey(t)
+	id1, err := peer.IDFromPrivateKey(key1)
+	require.NoError(err)
+	genesis := &types.GenesisDoc{ChainID: "test"}
+
+	node1, err := NewNode(context.Background(), config.NodeConfig{
+		P2P: config.P2PConfig{
+			ListenAddress: "/ip4/127.0.0.1/tcp/9017",
+			ConfirmGossip: true,
+		},
+		TxGossipRetryInterval: 50 * time.Millisecond,
+	}, key1, proxy.NewLocalClientCreator(app), genesis, log.TestingLogger())
+	require.NoError(err)
+	require.NoError(node1.Start())
+	defer func() { assert.NoError(node1.Stop()) }()
+
+	// transaction received with TTL 1 is not relayed, other transactions are relayed with decremented TTL
+	require.NoError(node1.Mempool.CheckTx([]byte("last hop"), nil, mempool.TxInfo{GossipTTL: 1}))
+	require.NoError(node1.Mempool.CheckTx([]byte("relayed"), nil, mempool.TxInfo{GossipTTL: 3}))
+
+	node2, err := NewNode(context.Background(), config.NodeConfig{
+		P2P: config.P2PConfig{
+			ListenAddress: "/ip4/127.0.0.1/tcp/9018",
+			Seeds:         "/ip4/127.0.0.1/tcp/9017/p2p/" + id1.Pretty(),
+		},
+	}, getKey(t), proxy.NewLocalClientCreator(app), genesis, log.TestingLogger())
+	require.NoError(err)
+	require.NoError(node2.Start())
+	defer func() { assert.NoError(node2.Stop()) }()
+
+	require.Eventually(func() bool {
+		return node2.Mempool.Size() == 1
+	}, 5*time.Second, 50*time.Millisecond)
+	time.Sleep(200 * time.Millisecond)
+	assert.Equal(1, node2.Mempool.Size())
+	memTx := node2.Mempool.TxsFront().Value.(*mempool.MempoolTx)
+	assert.Equal([]byte("relayed"), []byte(memTx.Tx))
+	assert.Equal(uint8(2), memTx.GossipTTL())
+}
+
+func TestErrors(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	app := &mocks.Application{}
+	app.On("CheckTx", mock.Anything).Return(abci.ResponseCheckTx{})
+	node, err := NewNode(context.Background(), config.NodeConfig{
+		P2P:                   config.P2PConfig{ConfirmGossip: true},
+		TxGossipRetryInterval: 10 * time.Millisecond,
+	}, getKey(t), proxy.NewLocalClientCreator(app), &types.GenesisDoc{ChainID: "test"}, log.TestingLogger())
+	require.NoError(err)
+	require.NoError(node.Start())
+	defer func() { assert.NoError(node.Stop()) }()
+
+	// node has no peers, so gossiping fails
+	require.NoError(node.Mempool.CheckTx([]byte("tx"), nil, mempool.TxInfo{}))
+	select {
+	case err := <-node.Errors():
+		assert.ErrorIs(err, ErrGossipTxs)
+		assert.ErrorIs(err, p2p.ErrNoPeers)
+		var opErr *OperationalError
+		require.True(errors.As(err, &opErr))
+		assert.Equal(ErrGossipTxs, opErr.Kind)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout while waiting for error")
+	}
+}
+
+func TestGossipTimeout(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	app := &mocks.Application{}
+	app.On("CheckTx", mock.Anything).Return(abci.ResponseCheckTx{})
+	droppedTxs := generic.NewCounter("dropped_gossip_txs")
+	metrics := NopMetrics()
+	metrics.DroppedGossipTxs = droppedTxs
+	// publish deadline passes immediately
+	node, err := NewNode(context.Background(), config.NodeConfig{
+		P2P:                   config.P2PConfig{PublishTimeout: time.Nanosecond},
+		TxGossipRetryInterval: time.Hour,
+	}, getKey(t), proxy.NewLocalClientCreator(app), &types.GenesisDoc{ChainID: "test"}, log.TestingLogger(), WithMetrics(metrics))
+	require.NoError(err)
+	require.NoError(node.Start())
+	defer func() { assert.NoError(node.Stop()) }()
+
+	// timed out transactions are dropped from gossip, not retried, so publish loop moves on
+	for i := 0; i < 5; i++ {
+		require.NoError(node.Mempool.CheckTx([]byte{byte(i)}, nil, mempool.TxInfo{}))
+	}
+	assert.Eventually(func() bool {
+		return droppedTxs.Value() > 0
+	}, 5*time.Second, 10*time.Millisecond)
+	select {
+	case err := <-node.Errors():
+		assert.ErrorIs(err, ErrGossipTxs)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout while waiting for error")
+	}
+	assert.Equal(5, node.Mempool.Size())
+}
+
+func TestGossipPermanentError(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	app := &mocks.Application{}
+	app.On("CheckTx", mock.Anything).Return(abci.ResponseCheckTx{})
+	droppedTxs := generic.NewCounter("dropped_gossip_txs")
+	metrics := NopMetrics()
+	metrics.DroppedGossipTxs = droppedTxs
+	// "bad" transaction is routed to lane that is not configured
+	router := func(tx []byte) string {
+		if string(tx) == "bad" {
+			return "unknown"
+		}
+		return ""
+	}
+	node, err := NewNode(context.Background(), config.NodeConfig{
+		TxGossipRetryInterval: 10 * time.Millisecond,
+	}, getKey(t), proxy.NewLocalClientCreator(app), &types.GenesisDoc{ChainID: "test"}, log.TestingLogger(),
+		WithMetrics(metrics), WithTxRouter(router))
+	require.NoError(err)
+	require.NoError(node.Start())
+	defer func() { assert.NoError(node.Stop()) }()
+
+	// bad transaction is dropped from gossip, not retried
+	require.NoError(node.Mempool.CheckTx([]byte("bad"), nil, mempool.TxInfo{}))
+	require.NoError(node.Mempool.CheckTx([]byte("good"), nil, mempool.TxInfo{}))
+	assert.Eventually(func() bool {
+		return droppedTxs.Value() == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(float64(1), droppedTxs.Value())
+	assert.Len(node.Errors(), 1)
+}
+
+func TestRetryableTxs(t *testing.T) {
+	txs := [][]byte{[]byte("tx1"), []byte("tx2"), []byte("tx3")}
+	cases := []struct {
+		name            string
+		err             error
+		expectedRetry   [][]byte
+		expectedDropped float64
+	}{
+		{"no peers", fmt.Errorf("wrapped: %w", p2p.ErrNoPeers), txs, 0},
+		{"timeout", p2p.ErrPublishTimeout, nil, 3},
+		{"partial failure", &p2p.TxGossipError{Failed: []p2p.FailedTxs{
+			{Txs: txs[:1], Err: p2p.ErrMessageTooLarge},
+			{Txs: txs[2:], Err: p2p.ErrNoPeers},
+		}}, txs[2:], 1},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			droppedTxs := generic.NewCounter("dropped_gossip_txs")
+			metrics := NopMetrics()
+			metrics.DroppedGossipTxs = droppedTxs
+			node, err := NewNode(context.Background(), config.NodeConfig{}, getKey(t), proxy.NewLocalClientCreator(&mocks.Application{}), &types.GenesisDoc{}, log.TestingLogger(),
+				WithMetrics(metrics))
+			require.NoError(err)
+
+			assert.Equal(c.expectedRetry, node.retryableTxs(c.err, txs))
+			assert.Equal(c.expectedDropped, droppedTxs.Value())
+		})
+	}
+}
+
+func TestDroppedErrors(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	node, err := NewNode(context.Background(), config.NodeConfig{}, getKey(t), proxy.NewLocalClientCreator(&mocks.Application{}), &types.GenesisDoc{}, log.TestingLogger())
+	require.NoError(err)
+
+	for i := 0; i < errorsCapacity+5; i++ {
+		node.reportError(fmt.Errorf("error %d", i))
+	}
+	assert.Len(node.Errors(), errorsCapacity)
+	assert.Equal(uint64(5), node.DroppedErrors())
+	assert.EqualError(<-node.Errors(), "error 0")
+}
+
+func TestNodeMode(t *testing.T) {
+	cases := []struct {
+		mode          string
+		expectedLoops []string
+		expectedErr   error
+	}{
+		{"", []string{"mempoolPublishLoop", "mempoolReadLoop", "watchdogLoop"}, nil},
+		{config.ModeFull, []string{"mempoolPublishLoop", "mempoolReadLoop", "watchdogLoop"}, nil},
+		{config.ModeAggregator, []string{"mempoolPublishLoop", "mempoolReadLoop", "watchdogLoop"}, nil},
+		{config.ModeArchive, []string{"watchdogLoop"}, nil},
+		{"light", nil, ErrInvalidMode},
+	}
+
+	for _, c := range cases {
+		t.Run(c.mode, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			node, err := NewNode(context.Background(), config.NodeConfig{Mode: c.mode}, getKey(t), proxy.NewLocalClientCreator(&mocks.Application{}), &types.GenesisDoc{}, log.TestingLogger())
+			if c.expectedErr != nil {
+				assert.ErrorIs(err, c.expectedErr)
+				assert.Nil(node)
+				return
+			}
+			require.NoError(err)
+			require.NoError(node.Start())
+			defer func() { assert.NoError(node.Stop()) }()
+
+			loops := make([]string, 0, len(node.loops))
+			for name := range node.loops {
+				loops = append(loops, name)
+			}
+			sort.Strings(loops)
+			assert.Equal(c.expectedLoops, loops)
+		})
+	}
+}
+
+func TestDAPollIntervalValidation(t *testing.T) {
+	cases := []struct {
+		name     string
+		interval time.Duration
+		expected time.Duration
+		err      error
+	}{
+		{"default", 0, config.DefaultDAPollInterval, nil},
+		{"configured", 100 * time.Millisecond, 100 * time.Millisecond, nil},
+		{"negative", -time.Second, 0, ErrInvalidDAPollInterval},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			conf := config.NodeConfig{DAPollInterval: c.interval}
+			node, err := NewNode(context.Background(), conf, getKey(t), proxy.NewLocalClientCreator(&mocks.Application{}), &types.GenesisDoc{}, log.TestingLogger())
+			if c.err != nil {
+				assert.ErrorIs(err, c.err)
+				assert.Nil(node)
+				return
+			}
+			require.NoError(err)
+			assert.Equal(c.expected, node.conf.DAPollInterval)
+		})
+	}
+}
+
+func TestGenesis(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	genesis := &types.GenesisDoc{
+		ChainID:         "test",
+		GenesisTime:     time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+		ConsensusParams: types.DefaultConsensusParams(),
+		InitialHeight:   1,
+		Validators:      []types.GenesisValidator{{Name: "aggregator", PubKey: ed25519.GenPrivKey().PubKey(), Power: 1}},
+		AppState:        []byte(`{"a":1}`),
+	}
+	expected := copyGenesis(genesis)
+
+	node, err := NewNode(context.Background(), config.NodeConfig{}, getKey(t), proxy.NewLocalClientCreator(&mocks.Application{}), genesis, log.TestingLogger())
+	require.NoError(err)
+
+	actual, err := node.Genesis()
+	require.NoError(err)
+	assert.Equal(expected, actual)
+
+	// returned copy can be modified safely
+	actual.ChainID = "modified"
+	actual.Validators[0].Power = 100
+	actual.ConsensusParams.Block.MaxBytes = 1
+	actual.AppState[0] = '['
+	actual, err = node.Genesis()
+	require.NoError(err)
+	assert.Equal(expected, actual)
+
+	// genesis document passed to NewNode is modified by the caller
+	genesis.ChainID = "modified"
+	_, err = node.Genesis()
+	assert.ErrorIs(err, ErrInvalidGenesis)
+}
+
+func TestGenesisValidation(t *testing.T) {
+	cases := []struct {
+		name    string
+		genesis *types.GenesisDoc
+	}{
+		{"no chain ID", &types.GenesisDoc{}},
+		{"validator without power", &types.GenesisDoc{
+			ChainID:    "test",
+			Validators: []types.GenesisValidator{{PubKey: ed25519.GenPrivKey().PubKey()}},
+		}},
+		{"validator without public key", &types.GenesisDoc{
+			ChainID:    "test",
+			Validators: []types.GenesisValidator{{Power: 1}},
+		}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			node, err := NewNode(context.Background(), config.NodeConfig{}, getKey(t), proxy.NewLocalClientCreator(&mocks.Application{}), c.genesis, log.TestingLogger())
+			require.NoError(t, err)
+			genesis, err := node.Genesis()
+			assert.ErrorIs(t, err, ErrInvalidGenesis)
+			assert.Nil(t, genesis)
+		})
+	}
+}
+
+func TestGenesisInitialHeight(t *testing.T) {
+	cases := []struct {
+		initialHeight int64
+		expected      int64
+	}{
+		{0, 1},
+		{1, 1},
+		{100, 100},
+	}
+
+	for _, c := range cases {
+		t.Run(fmt.Sprint(c.initialHeight), func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			genesis := &types.GenesisDoc{ChainID: "test", InitialHeight: c.initialHeight}
+			node, err := NewNode(context.Background(), config.NodeConfig{}, getKey(t), proxy.NewLocalClientCreator(&mocks.Application{}), genesis, log.TestingLogger())
+			require.NoError(err)
+			actual, err := node.Genesis()
+			require.NoError(err)
+			assert.Equal(c.expected, actual.InitialHeight)
+			// genesis document passed by caller is not modified
+			assert.Equal(c.initialHeight, genesis.InitialHeight)
+		})
+	}
+}
+
+func TestStartupSummary(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	conf := config.NodeConfig{
+		Mode:    config.ModeAggregator,
+		DALayer: "mock",
+		P2P:     config.P2PConfig{ListenAddress: "/ip4/127.0.0.1/tcp/0"},
+	}
+	genesis := &types.GenesisDoc{ChainID: "test"}
+	node, err := NewNode(context.Background(), conf, getKey(t), proxy.NewLocalClientCreator(&mocks.Application{}), genesis, log.TestingLogger())
+	require.NoError(err)
+	require.NoError(node.Start())
+	defer func() { assert.NoError(node.Stop()) }()
+
+	summary := func() map[string]interface{} {
+		keyvals := node.startupSummary()
+		require.Zero(len(keyvals) % 2)
+		m := make(map[string]interface{})
+		for i := 0; i < len(keyvals); i += 2 {
+			m[keyvals[i].(string)] = keyvals[i+1]
+		}
+		return m
+	}
+
+	s := summary()
+	assert.Equal("test", s["chainID"])
+	assert.Equal(config.ModeAggregator, s["mode"])
+	assert.Equal(true, s["aggregator"])
+	assert.Equal("mock", s["daLayer"])
+	assert.NotEmpty(s["listenAddrs"])
+	assert.Equal(int64(1), s["startHeight"])
+
+	require.NoError(node.BlockStore.SaveBlock(&optimint.Block{Header: optimint.Header{Height: 1}}))
+	assert.Equal(int64(2), summary()["startHeight"])
+}
+
+func TestNamespaceID(t *testing.T) {
+	cases := []struct {
+		name       string
+		configured [8]byte
+		expected   [8]byte
+	}{
+		{"derived from chain ID", [8]byte{}, optimint.ChainNamespaceID("test")},
+		{"explicitly configured", [8]byte{1, 2, 3, 4, 5, 6, 7, 8}, [8]byte{1, 2, 3, 4, 5, 6, 7, 8}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			node, err := NewNode(context.Background(), config.NodeConfig{NamespaceID: c.configured}, getKey(t), proxy.NewLocalClientCreator(&mocks.Application{}), &types.GenesisDoc{ChainID: "test"}, log.TestingLogger())
+			require.NoError(t, err)
+			assert.Equal(t, c.expected, node.NamespaceID())
+		})
+	}
+}
+
+func TestNamespaceIDAt(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	base := [8]byte{1, 2, 3, 4, 5, 6, 7, 8}
+	node, err := NewNode(context.Background(), config.NodeConfig{NamespaceID: base, NamespaceEpochLength: 10}, getKey(t), proxy.NewLocalClientCreator(&mocks.Application{}), &types.GenesisDoc{ChainID: "test"}, log.TestingLogger())
+	require.NoError(err)
+
+	assert.Equal(optimint.EpochNamespaceID(base, 10, 1), node.NamespaceIDAt(1))
+	assert.Equal(node.NamespaceIDAt(1), node.NamespaceIDAt(10))
+	assert.NotEqual(node.NamespaceIDAt(10), node.NamespaceIDAt(11))
+	assert.Equal(base, node.NamespaceID())
+}
@@ -0,0 +1,111 @@
+package node
+
+import (
+	"context"
+	"time"
+
+	"github.com/lazyledger/lazyledger-core/types"
+
+	"github.com/lazyledger/optimint/da"
+	optimint "github.com/lazyledger/optimint/types"
+)
+
+// daSyncLoop retrieves blocks of the chain from DA layer and saves them in block store, in chain order.
+//
+// Every DAPollInterval, all DA layer heights available since previous poll are read. The last available height is
+// read again on next poll, because more blocks can be included in it. Blocks that are already stored are skipped.
+func (n *Node) daSyncLoop(ctx context.Context) {
+	const name = "daSyncLoop"
+	defer n.watchdog.idle(name)
+
+	ticker := time.NewTicker(n.conf.DAPollInterval)
+	defer ticker.Stop()
+
+	for {
+		n.watchdog.active(name)
+		n.syncDA(ctx)
+		n.watchdog.idle(name)
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// syncDA reads DA layer heights starting from daSyncHeight, until height that is not available yet.
+func (n *Node) syncDA(ctx context.Context) {
+	for daHeight := n.daSyncHeight; ctx.Err() == nil; daHeight++ {
+		if !n.syncDAHeight(daHeight) {
+			return
+		}
+		n.daSyncHeight = daHeight
+	}
+}
+
+// syncDAHeight applies all blocks of the chain included at given DA layer height. It returns false if DA height
+// couldn't be retrieved.
+//
+// Blocks are retrieved from namespace of the next block (see NamespaceIDAt). If applied blocks reach the end of
+// namespace epoch, DA height is read again in namespace of the following epoch.
+func (n *Node) syncDAHeight(daHeight uint64) bool {
+	for {
+		next := n.nextHeight()
+		namespaceID := n.NamespaceIDAt(next)
+		res := n.dalc.RetrieveBlocks(daHeight, namespaceID)
+		if res.Code != da.StatusSuccess {
+			n.Logger.Debug("DA height not retrieved", "daHeight", daHeight, "code", res.Code, "message", res.Message)
+			return false
+		}
+		n.applyBlocks(daHeight, res.Blocks)
+		if n.nextHeight() == next || n.NamespaceIDAt(n.nextHeight()) == namespaceID {
+			return true
+		}
+	}
+}
+
+// applyBlocks saves blocks retrieved from DA layer, that continue the chain in block store.
+//
+// Blocks are expected in chain order. Blocks below the next height are skipped as already applied. Blocks that
+// are invalid or don't link to the previous block (for example, submitted by someone else than the aggregator) are
+// reported and skipped, so a valid block at the same height can still be applied.
+func (n *Node) applyBlocks(daHeight uint64, blocks []*optimint.Block) {
+	aggregator := n.aggregatorKey()
+	for _, block := range blocks {
+		next := n.nextHeight()
+		if block.Header.Height < next {
+			continue
+		}
+
+		var prev *optimint.Block
+		if n.BlockStore.Height() > 0 {
+			var err error
+			prev, err = n.BlockStore.LoadBlock(next - 1)
+			if err != nil {
+				n.Logger.Error("failed to load block", "height", next-1, "error", err)
+				return
+			}
+		}
+		if err := n.verifyBlock(next, prev, block, aggregator); err != nil {
+			n.Logger.Info("skipping invalid block from DA layer", "daHeight", daHeight, "height", block.Header.Height, "error", err)
+			continue
+		}
+		if err := n.BlockStore.SaveBlock(block); err != nil {
+			n.Logger.Error("failed to save block", "height", next, "error", err)
+			return
+		}
+		// there is no block execution yet, so only block store height is signaled (see WaitForHeight)
+		if err := n.eventBus.PublishEventNewBlock(types.EventDataNewBlock{}); err != nil {
+			n.Logger.Error("failed to publish NewBlock event", "height", next, "error", err)
+		}
+		n.Logger.Debug("applied block from DA layer", "daHeight", daHeight, "height", next)
+	}
+}
+
+// nextHeight returns height of the next block to be added to block store.
+func (n *Node) nextHeight() uint64 {
+	if height := n.BlockStore.Height(); height > 0 {
+		return height + 1
+	}
+	return uint64(n.genesis.InitialHeight)
+}
@@ -0,0 +1,167 @@
+package node
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lazyledger/lazyledger-core/crypto/ed25519"
+	"github.com/lazyledger/lazyledger-core/libs/log"
+	"github.com/lazyledger/lazyledger-core/proxy"
+	"github.com/lazyledger/lazyledger-core/types"
+
+	"github.com/lazyledger/optimint/config"
+	"github.com/lazyledger/optimint/da"
+	damock "github.com/lazyledger/optimint/da/mock"
+	"github.com/lazyledger/optimint/mocks"
+	optimint "github.com/lazyledger/optimint/types"
+)
+
+func TestSyncMultipleBlocksPerDAHeight(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	aggregator := ed25519.GenPrivKey()
+	dalc := &damock.MockDataAvailabilityLayerClient{}
+	require.NoError(dalc.Init(nil, log.TestingLogger()))
+
+	genesis := &types.GenesisDoc{
+		ChainID:    "test",
+		Validators: []types.GenesisValidator{{PubKey: aggregator.PubKey(), Power: 1}},
+	}
+	node, err := NewNode(context.Background(), config.NodeConfig{}, getKey(t), proxy.NewLocalClientCreator(&mocks.Application{}), genesis, log.TestingLogger(), WithDALayerClient(dalc))
+	require.NoError(err)
+
+	blocks := getSignedChain(t, 4, aggregator)
+	for _, b := range blocks {
+		b.Header.NamespaceID = node.NamespaceID()
+	}
+	blocks = relinkChain(t, blocks, aggregator)
+
+	// block that doesn't link to the chain, included before the valid block at the same height
+	forged := *blocks[1]
+	forged.Header.LastHeaderHash = [32]byte{1}
+
+	// three blocks (and a forged one) at first DA height, submitted out of order
+	for _, b := range []*optimint.Block{blocks[2], &forged, blocks[0], blocks[1]} {
+		require.Equal(da.StatusSuccess, dalc.SubmitBlock(b).Code)
+	}
+
+	require.NoError(node.Start())
+	defer func() {
+		require.NoError(node.Stop())
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(node.WaitForHeight(ctx, 3))
+	for i, b := range blocks[:3] {
+		stored, err := node.BlockStore.LoadBlock(uint64(i + 1))
+		require.NoError(err)
+		assert.Equal(b.Header.Hash(), stored.Header.Hash())
+	}
+
+	// next DA height
+	dalc.AdvanceHeight()
+	require.Equal(da.StatusSuccess, dalc.SubmitBlock(blocks[3]).Code)
+	require.NoError(node.WaitForHeight(ctx, 4))
+	assert.Equal(uint64(4), node.BlockStore.Height())
+	assert.NoError(node.VerifyChain(1, 4))
+}
+
+func TestSyncNamespaceEpochs(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	aggregator := ed25519.GenPrivKey()
+	dalc := &damock.MockDataAvailabilityLayerClient{}
+	require.NoError(dalc.Init(nil, log.TestingLogger()))
+
+	genesis := &types.GenesisDoc{ChainID: "test"}
+	conf := config.NodeConfig{NamespaceEpochLength: 2}
+	node, err := NewNode(context.Background(), conf, getKey(t), proxy.NewLocalClientCreator(&mocks.Application{}), genesis, log.TestingLogger(), WithDALayerClient(dalc))
+	require.NoError(err)
+
+	blocks := getSignedChain(t, 5, aggregator)
+	for _, b := range blocks {
+		b.Header.NamespaceID = node.NamespaceIDAt(b.Header.Height)
+	}
+	blocks = relinkChain(t, blocks, aggregator)
+	require.NotEqual(blocks[1].Header.NamespaceID, blocks[2].Header.NamespaceID)
+
+	// all blocks at single DA height, spread over three namespaces
+	for _, b := range blocks {
+		require.Equal(da.StatusSuccess, dalc.SubmitBlock(b).Code)
+	}
+
+	require.True(node.syncDAHeight(0))
+	assert.Equal(uint64(5), node.BlockStore.Height())
+	assert.NoError(node.VerifyChain(1, 5))
+
+	// DA height above current one is not available
+	assert.False(node.syncDAHeight(1))
+}
+
+// relinkChain re-links and re-signs blocks after their headers were modified.
+func relinkChain(t *testing.T, blocks []*optimint.Block, aggregator ed25519.PrivKey) []*optimint.Block {
+	for i := 1; i < len(blocks); i++ {
+		prevHash := blocks[i-1].Header.Hash()
+		sig, err := aggregator.Sign(prevHash[:])
+		require.NoError(t, err)
+		blocks[i].Header.LastHeaderHash = prevHash
+		blocks[i].LastCommit = &optimint.Commit{
+			Height:     uint64(i),
+			HeaderHash: prevHash,
+			Signatures: []optimint.Signature{sig},
+		}
+	}
+	return blocks
+}
+
+func TestSyncPollInterval(t *testing.T) {
+	cases := []struct {
+		name     string
+		interval time.Duration
+		// expected bounds of retrievals in the first 500ms; every poll retrieves current DA height, and fails to
+		// retrieve the next one
+		min, max int64
+	}{
+		{"single poll", time.Hour, 2, 2},
+		{"frequent polls", 50 * time.Millisecond, 10, 22},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			require := require.New(t)
+
+			dalc := &countingDALC{MockDataAvailabilityLayerClient: &damock.MockDataAvailabilityLayerClient{}}
+			require.NoError(dalc.Init(nil, log.TestingLogger()))
+
+			conf := config.NodeConfig{DAPollInterval: c.interval}
+			node, err := NewNode(context.Background(), conf, getKey(t), proxy.NewLocalClientCreator(&mocks.Application{}), &types.GenesisDoc{ChainID: "test"}, log.TestingLogger(), WithDALayerClient(dalc))
+			require.NoError(err)
+			require.NoError(node.Start())
+			time.Sleep(500 * time.Millisecond)
+			require.NoError(node.Stop())
+
+			retrievals := atomic.LoadInt64(&dalc.retrievals)
+			assert.GreaterOrEqual(t, retrievals, c.min)
+			assert.LessOrEqual(t, retrievals, c.max)
+		})
+	}
+}
+
+// countingDALC counts block retrievals.
+type countingDALC struct {
+	*damock.MockDataAvailabilityLayerClient
+	retrievals int64
+}
+
+func (c *countingDALC) RetrieveBlocks(daHeight uint64, namespaceID [8]byte) da.ResultRetrieveBlocks {
+	atomic.AddInt64(&c.retrievals, 1)
+	return c.MockDataAvailabilityLayerClient.RetrieveBlocks(daHeight, namespaceID)
+}
@@ -0,0 +1,130 @@
+// Package testutil provides helpers for constructing nodes in tests.
+package testutil
+
+import (
+	"context"
+	"crypto/rand"
+	"testing"
+
+	"github.com/lazyledger/lazyledger-core/abci/example/kvstore"
+	abci "github.com/lazyledger/lazyledger-core/abci/types"
+	"github.com/lazyledger/lazyledger-core/libs/log"
+	"github.com/lazyledger/lazyledger-core/proxy"
+	"github.com/lazyledger/lazyledger-core/types"
+	"github.com/libp2p/go-libp2p-core/crypto"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lazyledger/optimint/config"
+	"github.com/lazyledger/optimint/da/mock"
+	"github.com/lazyledger/optimint/node"
+	optimint "github.com/lazyledger/optimint/types"
+)
+
+// TestChainID is the chain ID used by default in test nodes.
+const TestChainID = "test-chain"
+
+type testNodeConfig struct {
+	ctx         context.Context
+	conf        config.NodeConfig
+	key         crypto.PrivKey
+	app         abci.Application
+	genesis     *types.GenesisDoc
+	logger      log.Logger
+	nodeOptions []node.Option
+}
+
+// Option overrides default parameters of test node.
+type Option func(*testNodeConfig)
+
+// WithContext sets context passed to node.
+func WithContext(ctx context.Context) Option {
+	return func(c *testNodeConfig) { c.ctx = ctx }
+}
+
+// WithConfig sets node configuration.
+func WithConfig(conf config.NodeConfig) Option {
+	return func(c *testNodeConfig) { c.conf = conf }
+}
+
+// WithKey sets node private key.
+func WithKey(key crypto.PrivKey) Option {
+	return func(c *testNodeConfig) { c.key = key }
+}
+
+// WithApp sets ABCI application, connected in-process.
+func WithApp(app abci.Application) Option {
+	return func(c *testNodeConfig) { c.app = app }
+}
+
+// WithGenesis sets genesis document.
+func WithGenesis(genesis *types.GenesisDoc) Option {
+	return func(c *testNodeConfig) { c.genesis = genesis }
+}
+
+// WithLogger sets node logger.
+func WithLogger(logger log.Logger) Option {
+	return func(c *testNodeConfig) { c.logger = logger }
+}
+
+// WithNodeOptions appends options passed to node.NewNode.
+func WithNodeOptions(options ...node.Option) Option {
+	return func(c *testNodeConfig) { c.nodeOptions = append(c.nodeOptions, options...) }
+}
+
+// NewTestNode creates (but doesn't start) a node for testing.
+//
+// By default, node uses in-memory block store, mock DA layer client, in-process kvstore application
+// and randomly generated key. P2P client listens on random local port. Defaults can be overridden with options.
+func NewTestNode(t testing.TB, opts ...Option) *node.Node {
+	t.Helper()
+	require := require.New(t)
+
+	dalc := &mock.MockDataAvailabilityLayerClient{}
+	c := &testNodeConfig{
+		ctx: context.Background(),
+		conf: config.NodeConfig{
+			P2P: config.P2PConfig{ListenAddress: "/ip4/127.0.0.1/tcp/0"},
+		},
+		app:         kvstore.NewApplication(),
+		genesis:     &types.GenesisDoc{ChainID: TestChainID},
+		logger:      log.TestingLogger(),
+		nodeOptions: []node.Option{node.WithDALayerClient(dalc)},
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	require.NoError(dalc.Init(nil, c.logger))
+	if c.key == nil {
+		key, _, err := crypto.GenerateEd25519Key(rand.Reader)
+		require.NoError(err)
+		c.key = key
+	}
+
+	n, err := node.NewNode(c.ctx, c.conf, c.key, proxy.NewLocalClientCreator(c.app), c.genesis, c.logger, c.nodeOptions...)
+	require.NoError(err)
+	require.NotNil(n)
+
+	return n
+}
+
+// FirstBlock returns the first block of the chain, consistent with genesis (see node.VerifyFirstBlock).
+//
+// Block is at genesis initial height, has genesis time and contains no transactions. It's in the namespace derived
+// from chain ID, as used by nodes without configured namespace ID and namespace epochs.
+func FirstBlock(genesis *types.GenesisDoc) *optimint.Block {
+	height := uint64(genesis.InitialHeight)
+	if height == 0 {
+		height = 1
+	}
+	block := &optimint.Block{
+		Header: optimint.Header{
+			NamespaceID: optimint.ChainNamespaceID(genesis.ChainID),
+			Height:      height,
+		},
+	}
+	if !genesis.GenesisTime.IsZero() {
+		block.Header.Time = uint64(genesis.GenesisTime.UnixNano())
+	}
+	block.Header.DataHash = block.Data.Hash()
+	return block
+}
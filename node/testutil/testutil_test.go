@@ -0,0 +1,77 @@
+package testutil
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lazyledger/lazyledger-core/types"
+
+	"github.com/lazyledger/optimint/config"
+	"github.com/lazyledger/optimint/node"
+	optimint "github.com/lazyledger/optimint/types"
+)
+
+func TestNewTestNode(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	n := NewTestNode(t)
+	require.NoError(n.Start())
+	assert.Equal(TestChainID, n.Info().Network)
+	require.NoError(n.Stop())
+
+	n = NewTestNode(t, WithConfig(config.NodeConfig{Moniker: "custom", P2P: config.P2PConfig{ListenAddress: "/ip4/127.0.0.1/tcp/0"}}))
+	require.NoError(n.Start())
+	assert.Equal("custom", n.Info().Moniker)
+	require.NoError(n.Stop())
+}
+
+func TestFirstBlock(t *testing.T) {
+	genesisTime := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		name        string
+		genesis     *types.GenesisDoc
+		tamper      func(block *optimint.Block)
+		expectedErr error
+	}{
+		{"consistent", &types.GenesisDoc{ChainID: TestChainID}, func(*optimint.Block) {}, nil},
+		{"consistent, with initial height and time",
+			&types.GenesisDoc{ChainID: TestChainID, InitialHeight: 100, GenesisTime: genesisTime},
+			func(*optimint.Block) {}, nil},
+		{"later than genesis",
+			&types.GenesisDoc{ChainID: TestChainID, GenesisTime: genesisTime},
+			func(block *optimint.Block) { block.Header.Time += uint64(time.Second) }, nil},
+		{"height below initial height",
+			&types.GenesisDoc{ChainID: TestChainID, InitialHeight: 100},
+			func(block *optimint.Block) { block.Header.Height = 1 }, node.ErrGenesisMismatch},
+		{"height above initial height",
+			&types.GenesisDoc{ChainID: TestChainID},
+			func(block *optimint.Block) { block.Header.Height = 2 }, node.ErrGenesisMismatch},
+		{"earlier than genesis",
+			&types.GenesisDoc{ChainID: TestChainID, GenesisTime: genesisTime},
+			func(block *optimint.Block) { block.Header.Time -= uint64(time.Second) }, node.ErrGenesisMismatch},
+		{"different namespace",
+			&types.GenesisDoc{ChainID: TestChainID},
+			func(block *optimint.Block) { block.Header.NamespaceID = optimint.ChainNamespaceID("other") }, node.ErrGenesisMismatch},
+		{"links to previous block",
+			&types.GenesisDoc{ChainID: TestChainID},
+			func(block *optimint.Block) { block.Header.LastHeaderHash = [32]byte{1, 2, 3} }, node.ErrGenesisMismatch},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			block := FirstBlock(c.genesis)
+			c.tamper(block)
+			err := node.VerifyFirstBlock(c.genesis, optimint.ChainNamespaceID(c.genesis.ChainID), block)
+			if c.expectedErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, c.expectedErr)
+			}
+		})
+	}
+}
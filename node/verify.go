@@ -0,0 +1,114 @@
+package node
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/lazyledger/lazyledger-core/crypto"
+	"github.com/lazyledger/lazyledger-core/types"
+
+	optimint "github.com/lazyledger/optimint/types"
+)
+
+// VerifyChain checks integrity of blocks stored at heights from..to (inclusive).
+//
+// For every block, header has to match the height, DataHash has to match block data and LastHeaderHash has to be
+// equal to hash of the previous header. If block contains LastCommit and genesis defines single validator (aggregator),
+// commit signature is verified as well. If genesis defines consensus params, blocks have to respect them (see
+// Block.ValidateParams); MaxGas is verified only if node was created with WithTxGas. Block at genesis initial height
+// is verified with VerifyFirstBlock. Error for the first broken height is returned.
+func (n *Node) VerifyChain(from, to uint64) error {
+	if from == 0 {
+		from = 1
+	}
+	aggregator := n.aggregatorKey()
+
+	var prev *optimint.Block
+	if from > 1 {
+		block, err := n.BlockStore.LoadBlock(from - 1)
+		if err != nil {
+			return fmt.Errorf("failed to load block at height %d: %w", from-1, err)
+		}
+		prev = block
+	}
+
+	for h := from; h <= to; h++ {
+		block, err := n.BlockStore.LoadBlock(h)
+		if err != nil {
+			return fmt.Errorf("failed to load block at height %d: %w", h, err)
+		}
+		if err := n.verifyBlock(h, prev, block, aggregator); err != nil {
+			return err
+		}
+		prev = block
+	}
+	return nil
+}
+
+// verifyBlock checks that block is valid at given height and links to prev (see VerifyChain). prev is nil if previous
+// block is not available.
+func (n *Node) verifyBlock(height uint64, prev, block *optimint.Block, aggregator crypto.PubKey) error {
+	if block.Header.Height != height {
+		return fmt.Errorf("%w: expected %d, got %d", ErrInvalidHeight, height, block.Header.Height)
+	}
+	if block.Header.DataHash != block.Data.Hash() {
+		return fmt.Errorf("%w at height %d", ErrDataHashMismatch, height)
+	}
+	if n.genesis.ConsensusParams != nil {
+		if err := block.ValidateParams(*n.genesis.ConsensusParams, n.txGas); err != nil {
+			return fmt.Errorf("invalid block at height %d: %w", height, err)
+		}
+	}
+	if height == uint64(n.genesis.InitialHeight) {
+		if err := VerifyFirstBlock(n.genesis, n.NamespaceIDAt(height), block); err != nil {
+			return err
+		}
+	}
+	if prev != nil {
+		if block.Header.LastHeaderHash != prev.Header.Hash() {
+			return fmt.Errorf("%w at height %d", ErrInvalidLinkage, height)
+		}
+		if block.LastCommit != nil && aggregator != nil {
+			if err := optimint.ValidateCommit(&prev.Header, block.LastCommit, aggregator); err != nil {
+				return fmt.Errorf("invalid last commit at height %d: %w", height, err)
+			}
+		}
+	}
+	return nil
+}
+
+// VerifyFirstBlock checks that the first block of the chain is consistent with genesis.
+//
+// Block has to be at genesis initial height, in namespaceID (namespace of the chain at initial height, see
+// Node.NamespaceIDAt), can't link to previous block and can't be older than genesis time (if genesis time is set).
+// Block time is interpreted as nanoseconds since Unix epoch.
+func VerifyFirstBlock(genesis *types.GenesisDoc, namespaceID [8]byte, block *optimint.Block) error {
+	initialHeight := uint64(genesis.InitialHeight)
+	if initialHeight == 0 {
+		initialHeight = 1
+	}
+	if block.Header.Height != initialHeight {
+		return fmt.Errorf("%w: height %d, initial height %d", ErrGenesisMismatch, block.Header.Height, initialHeight)
+	}
+	if block.Header.NamespaceID != namespaceID {
+		return fmt.Errorf("%w: namespace ID %X, expected %X", ErrGenesisMismatch, block.Header.NamespaceID, namespaceID)
+	}
+	if block.Header.LastHeaderHash != [32]byte{} {
+		return fmt.Errorf("%w: block links to previous block %X", ErrGenesisMismatch, block.Header.LastHeaderHash)
+	}
+	if !genesis.GenesisTime.IsZero() {
+		blockTime := time.Unix(0, int64(block.Header.Time))
+		if blockTime.Before(genesis.GenesisTime) {
+			return fmt.Errorf("%w: block time %s before genesis time %s", ErrGenesisMismatch, blockTime.UTC(), genesis.GenesisTime.UTC())
+		}
+	}
+	return nil
+}
+
+// aggregatorKey returns public key of the aggregator, if genesis defines exactly one validator.
+func (n *Node) aggregatorKey() crypto.PubKey {
+	if len(n.genesis.Validators) != 1 {
+		return nil
+	}
+	return n.genesis.Validators[0].PubKey
+}
@@ -0,0 +1,163 @@
+package node
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lazyledger/lazyledger-core/crypto/ed25519"
+	"github.com/lazyledger/lazyledger-core/libs/log"
+	"github.com/lazyledger/lazyledger-core/proxy"
+	"github.com/lazyledger/lazyledger-core/types"
+
+	"github.com/lazyledger/optimint/config"
+	"github.com/lazyledger/optimint/mocks"
+	"github.com/lazyledger/optimint/store"
+	optimint "github.com/lazyledger/optimint/types"
+)
+
+func TestVerifyChain(t *testing.T) {
+	aggregator := ed25519.GenPrivKey()
+	rogue := ed25519.GenPrivKey()
+	const maxBytes = 10000
+
+	cases := []struct {
+		name        string
+		tamper      func(blocks []*optimint.Block)
+		expectedErr error
+	}{
+		{"valid chain", func([]*optimint.Block) {}, nil},
+		{"tampered data", func(blocks []*optimint.Block) {
+			blocks[2].Data.Txs[0] = optimint.Tx("evil tx")
+		}, ErrDataHashMismatch},
+		{"tampered header", func(blocks []*optimint.Block) {
+			blocks[1].Header.Time++
+		}, ErrInvalidLinkage},
+		{"missing block", func(blocks []*optimint.Block) {
+			// block at height 7 would create a gap and is rejected by store, so height 4 is missing
+			blocks[3].Header.Height = 7
+		}, store.ErrKeyNotFound},
+		{"first block with parent", func(blocks []*optimint.Block) {
+			blocks[0].Header.LastHeaderHash = [32]byte{1}
+		}, ErrGenesisMismatch},
+		{"first block in other namespace", func(blocks []*optimint.Block) {
+			blocks[0].Header.NamespaceID = optimint.ChainNamespaceID("other")
+		}, ErrGenesisMismatch},
+		{"forged commit", func(blocks []*optimint.Block) {
+			hash := blocks[2].Header.Hash()
+			sig, err := rogue.Sign(hash[:])
+			require.NoError(t, err)
+			blocks[3].LastCommit.Signatures = []optimint.Signature{sig}
+		}, optimint.ErrInvalidSignature},
+		{"block exceeding max bytes", func(blocks []*optimint.Block) {
+			blocks[4].Data.Txs = append(blocks[4].Data.Txs, make(optimint.Tx, 2*maxBytes))
+			blocks[4].Header.DataHash = blocks[4].Data.Hash()
+		}, optimint.ErrBlockTooLarge},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			genesis := &types.GenesisDoc{
+				ChainID:    "test",
+				Validators: []types.GenesisValidator{{PubKey: aggregator.PubKey(), Power: 1}},
+			}
+			genesis.ConsensusParams = types.DefaultConsensusParams()
+			genesis.ConsensusParams.Block.MaxBytes = maxBytes
+			node, err := NewNode(context.Background(), config.NodeConfig{}, getKey(t), proxy.NewLocalClientCreator(&mocks.Application{}), genesis, log.TestingLogger())
+			require.NoError(err)
+
+			blocks := getSignedChain(t, 5, aggregator)
+			for _, b := range blocks {
+				b.Header.NamespaceID = node.NamespaceIDAt(b.Header.Height)
+			}
+			blocks = relinkChain(t, blocks, aggregator)
+			c.tamper(blocks)
+			for _, b := range blocks {
+				// store rejects blocks that would create a gap
+				if err := node.BlockStore.SaveBlock(b); err != nil {
+					require.ErrorIs(err, store.ErrNonContiguousHeight)
+				}
+			}
+
+			err = node.VerifyChain(1, 5)
+			if c.expectedErr == nil {
+				assert.NoError(err)
+			} else {
+				assert.ErrorIs(err, c.expectedErr)
+			}
+		})
+	}
+}
+
+func TestVerifyChainMaxGas(t *testing.T) {
+	aggregator := ed25519.GenPrivKey()
+	genesis := &types.GenesisDoc{ChainID: "test"}
+	genesis.ConsensusParams = types.DefaultConsensusParams()
+	// every block of getSignedChain has two transactions
+	genesis.ConsensusParams.Block.MaxGas = 15
+
+	cases := []struct {
+		name        string
+		options     []Option
+		expectedErr error
+	}{
+		{"gas not verified", nil, nil},
+		{"gas verified", []Option{WithTxGas(func(optimint.Tx) int64 { return 5 })}, nil},
+		{"gas exceeded", []Option{WithTxGas(func(optimint.Tx) int64 { return 10 })}, optimint.ErrBlockGasExceeded},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			node, err := NewNode(context.Background(), config.NodeConfig{}, getKey(t), proxy.NewLocalClientCreator(&mocks.Application{}), genesis, log.TestingLogger(), c.options...)
+			require.NoError(t, err)
+
+			blocks := getSignedChain(t, 3, aggregator)
+			for _, b := range blocks {
+				b.Header.NamespaceID = node.NamespaceIDAt(b.Header.Height)
+			}
+			for _, b := range relinkChain(t, blocks, aggregator) {
+				require.NoError(t, node.BlockStore.SaveBlock(b))
+			}
+
+			err = node.VerifyChain(1, 3)
+			if c.expectedErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, c.expectedErr)
+			}
+		})
+	}
+}
+
+// getSignedChain returns n linked blocks (starting from height 1), with last commits signed by aggregator.
+func getSignedChain(t *testing.T, n int, aggregator ed25519.PrivKey) []*optimint.Block {
+	blocks := make([]*optimint.Block, n)
+	for i := range blocks {
+		block := &optimint.Block{
+			Header: optimint.Header{
+				Height:          uint64(i + 1),
+				ProposerAddress: aggregator.PubKey().Address(),
+			},
+			Data: optimint.Data{Txs: optimint.Txs{optimint.Tx{byte(i)}, optimint.Tx("tx")}},
+		}
+		block.Header.DataHash = block.Data.Hash()
+		if i > 0 {
+			prevHash := blocks[i-1].Header.Hash()
+			sig, err := aggregator.Sign(prevHash[:])
+			require.NoError(t, err)
+			block.Header.LastHeaderHash = prevHash
+			block.LastCommit = &optimint.Commit{
+				Height:     uint64(i),
+				HeaderHash: prevHash,
+				Signatures: []optimint.Signature{sig},
+			}
+		}
+		blocks[i] = block
+	}
+	return blocks
+}
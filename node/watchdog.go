@@ -0,0 +1,127 @@
+package node
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// watchdog tracks activity of node loops, to detect loops that are stuck while processing.
+//
+// Loop is either idle (waiting for input) or active. Idle loops are never considered stalled. Active loop is stalled,
+// if it didn't report progress for longer than stall timeout.
+type watchdog struct {
+	mtx sync.Mutex
+	// lastActive maps names of active loops to time of last reported progress
+	lastActive map[string]time.Time
+	// stalled contains names of loops that were stalled during last check
+	stalled map[string]bool
+}
+
+func newWatchdog() *watchdog {
+	return &watchdog{
+		lastActive: make(map[string]time.Time),
+		stalled:    make(map[string]bool),
+	}
+}
+
+// active records progress of the loop.
+func (w *watchdog) active(name string) {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+
+	w.lastActive[name] = time.Now()
+}
+
+// idle marks the loop as waiting for input.
+func (w *watchdog) idle(name string) {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+
+	delete(w.lastActive, name)
+}
+
+// check updates and returns sorted names of loops that didn't report progress since given time.
+// Loops that stalled since previous check are returned as newlyStalled, loops that made progress since previous
+// check are returned as recovered.
+func (w *watchdog) check(since time.Time) (stalled, newlyStalled, recovered []string) {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+
+	current := make(map[string]bool)
+	for name, last := range w.lastActive {
+		if last.Before(since) {
+			current[name] = true
+			stalled = append(stalled, name)
+			if !w.stalled[name] {
+				newlyStalled = append(newlyStalled, name)
+			}
+		}
+	}
+	for name := range w.stalled {
+		if !current[name] {
+			recovered = append(recovered, name)
+		}
+	}
+	w.stalled = current
+
+	sort.Strings(stalled)
+	sort.Strings(newlyStalled)
+	sort.Strings(recovered)
+	return
+}
+
+// stalledLoops returns sorted names of loops found stalled during last check.
+func (w *watchdog) stalledLoops() []string {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+
+	var stalled []string
+	for name := range w.stalled {
+		stalled = append(stalled, name)
+	}
+	sort.Strings(stalled)
+	return stalled
+}
+
+// StalledLoops returns names of node loops that didn't make progress within LoopStallTimeout.
+// Empty result means that all loops are healthy.
+func (n *Node) StalledLoops() []string {
+	return n.watchdog.stalledLoops()
+}
+
+// watchdogLoop periodically checks for stalled loops, logs changes of loop health and updates metrics.
+//
+// Counters maintained by P2P client are also collected into metrics.
+func (n *Node) watchdogLoop(ctx context.Context) {
+	ticker := time.NewTicker(n.conf.LoopStallTimeout / 2)
+	defer ticker.Stop()
+
+	var pingFailures, rejectedStreams uint64
+
+	for {
+		select {
+		case now := <-ticker.C:
+			stalled, newlyStalled, recovered := n.watchdog.check(now.Add(-n.conf.LoopStallTimeout))
+			for _, name := range newlyStalled {
+				n.Logger.Error("loop stalled", "loop", name, "timeout", n.conf.LoopStallTimeout)
+				n.reportError(&OperationalError{Kind: ErrLoopStalled, Detail: fmt.Sprintf("%s, timeout %s", name, n.conf.LoopStallTimeout)})
+			}
+			for _, name := range recovered {
+				n.Logger.Info("loop recovered", "loop", name)
+			}
+			n.metrics.StalledLoops.Set(float64(len(stalled)))
+
+			current := n.P2P.PingFailures()
+			n.metrics.PingFailures.Add(float64(current - pingFailures))
+			pingFailures = current
+			current = n.P2P.RejectedStreams()
+			n.metrics.RejectedStreams.Add(float64(current - rejectedStreams))
+			rejectedStreams = current
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package node
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-kit/kit/metrics/generic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	abci "github.com/lazyledger/lazyledger-core/abci/types"
+	"github.com/lazyledger/lazyledger-core/libs/log"
+	"github.com/lazyledger/lazyledger-core/proxy"
+	"github.com/lazyledger/lazyledger-core/types"
+
+	"github.com/lazyledger/optimint/config"
+	"github.com/lazyledger/optimint/mempool"
+	"github.com/lazyledger/optimint/mocks"
+	"github.com/lazyledger/optimint/p2p"
+)
+
+func TestWatchdog(t *testing.T) {
+	assert := assert.New(t)
+
+	w := newWatchdog()
+	start := time.Now()
+	w.active("a")
+	w.active("b")
+	w.active("c")
+	w.idle("c")
+
+	stalled, newlyStalled, recovered := w.check(start)
+	assert.Empty(stalled)
+	assert.Empty(newlyStalled)
+	assert.Empty(recovered)
+
+	stalled, newlyStalled, recovered = w.check(time.Now())
+	assert.Equal([]string{"a", "b"}, stalled)
+	assert.Equal([]string{"a", "b"}, newlyStalled)
+	assert.Empty(recovered)
+	assert.Equal([]string{"a", "b"}, w.stalledLoops())
+
+	w.active("a")
+	stalled, newlyStalled, recovered = w.check(time.Now().Add(-time.Hour))
+	assert.Empty(stalled)
+	assert.Empty(newlyStalled)
+	assert.Equal([]string{"a", "b"}, recovered)
+	assert.Empty(w.stalledLoops())
+}
+
+func TestStalledLoop(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	stalledLoops := generic.NewGauge("stalled_loops")
+	metrics := NopMetrics()
+	metrics.StalledLoops = stalledLoops
+	conf := config.NodeConfig{LoopStallTimeout: 100 * time.Millisecond}
+	node, err := NewNode(context.Background(), conf, getKey(t), proxy.NewLocalClientCreator(&mocks.Application{}), &types.GenesisDoc{}, log.TestingLogger(),
+		WithMetrics(metrics))
+	require.NoError(err)
+	mp := &blockingMempool{Mempool: node.Mempool, unblock: make(chan struct{})}
+	node.Mempool = mp
+
+	require.NoError(node.Start())
+	defer func() { assert.NoError(node.Stop()) }()
+
+	// idle loops are not stalled
+	time.Sleep(3 * conf.LoopStallTimeout)
+	assert.Empty(node.StalledLoops())
+	assert.Equal(float64(0), stalledLoops.Value())
+
+	node.incomingTxCh <- &p2p.Tx{Data: []byte{1, 2, 3}, From: getPeerID(t)}
+	assert.Eventually(func() bool {
+		return len(node.StalledLoops()) == 1
+	}, 10*conf.LoopStallTimeout, conf.LoopStallTimeout/10)
+	assert.Equal([]string{"mempoolReadLoop"}, node.StalledLoops())
+	assert.Equal(float64(1), stalledLoops.Value())
+
+	close(mp.unblock)
+	assert.Eventually(func() bool {
+		return len(node.StalledLoops()) == 0
+	}, 10*conf.LoopStallTimeout, conf.LoopStallTimeout/10)
+	assert.Equal(float64(0), stalledLoops.Value())
+}
+
+// blockingMempool blocks CheckTx until unblock is closed.
+type blockingMempool struct {
+	mempool.Mempool
+
+	unblock chan struct{}
+}
+
+func (m *blockingMempool) CheckTx(types.Tx, func(*abci.Response), mempool.TxInfo) error {
+	<-m.unblock
+	return nil
+}
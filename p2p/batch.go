@@ -0,0 +1,72 @@
+package p2p
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// encodeTxBatch encodes multiple transactions into payload of single MsgTxBatch message.
+//
+// Every transaction is prefixed with its length, encoded as unsigned varint.
+func encodeTxBatch(txs [][]byte) []byte {
+	size := 0
+	for _, tx := range txs {
+		size += binary.MaxVarintLen64 + len(tx)
+	}
+	buf := make([]byte, 0, size)
+	lenBuf := make([]byte, binary.MaxVarintLen64)
+	for _, tx := range txs {
+		n := binary.PutUvarint(lenBuf, uint64(len(tx)))
+		buf = append(buf, lenBuf[:n]...)
+		buf = append(buf, tx...)
+	}
+	return buf
+}
+
+// splitTxBatch splits transactions into groups, that can be sent in messages with payload of at most maxSize bytes.
+// Single transaction is sent without length prefix (as MsgTx). Transaction larger than maxSize is put in separate group.
+func splitTxBatch(txs [][]byte, maxSize int) [][][]byte {
+	var groups [][][]byte
+	var group [][]byte
+	size := 0
+	for _, tx := range txs {
+		txSize := uvarintSize(uint64(len(tx))) + len(tx)
+		if len(group) > 0 && size+txSize > maxSize {
+			groups = append(groups, group)
+			group, size = nil, 0
+		}
+		group = append(group, tx)
+		size += txSize
+	}
+	if len(group) > 0 {
+		groups = append(groups, group)
+	}
+	return groups
+}
+
+// uvarintSize returns number of bytes of x encoded as unsigned varint.
+func uvarintSize(x uint64) int {
+	n := 1
+	for ; x >= 0x80; x >>= 7 {
+		n++
+	}
+	return n
+}
+
+// decodeTxBatch splits payload of MsgTxBatch message into individual transactions.
+func decodeTxBatch(data []byte) ([][]byte, error) {
+	var txs [][]byte
+	for len(data) > 0 {
+		l, n := binary.Uvarint(data)
+		if n <= 0 {
+			return nil, fmt.Errorf("%w: malformed length of tx %d", ErrInvalidTxBatch, len(txs))
+		}
+		data = data[n:]
+		if l > uint64(len(data)) {
+			return nil, fmt.Errorf("%w: tx %d exceeds batch (%d > %d bytes)", ErrInvalidTxBatch, len(txs), l, len(data))
+		}
+		txs = append(txs, data[:l])
+		data = data[l:]
+	}
+	return txs, nil
+}
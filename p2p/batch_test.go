@@ -0,0 +1,79 @@
+package p2p
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTxBatchRoundTrip(t *testing.T) {
+	cases := []struct {
+		name string
+		txs  [][]byte
+	}{
+		{"single", [][]byte{[]byte("tx1")}},
+		{"multiple", [][]byte{[]byte("tx1"), []byte("a bit longer tx2"), {0}}},
+		{"large", [][]byte{make([]byte, 1000), make([]byte, 70000)}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			txs, err := decodeTxBatch(encodeTxBatch(c.txs))
+			require.NoError(t, err)
+			assert.Equal(t, c.txs, txs)
+		})
+	}
+}
+
+func TestSplitTxBatch(t *testing.T) {
+	tx := func(size int) []byte { return make([]byte, size) }
+	cases := []struct {
+		name     string
+		txs      [][]byte
+		maxSize  int
+		expected []int
+	}{
+		{"empty", nil, 100, nil},
+		{"fits", [][]byte{tx(10), tx(20), tx(30)}, 100, []int{3}},
+		{"exactly fits", [][]byte{tx(49), tx(49)}, 100, []int{2}},
+		{"split", [][]byte{tx(49), tx(50), tx(10)}, 100, []int{1, 2}},
+		{"oversized tx", [][]byte{tx(10), tx(200), tx(10)}, 100, []int{1, 1, 1}},
+		{"single tx per message", [][]byte{tx(60), tx(60), tx(60)}, 100, []int{1, 1, 1}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			groups := splitTxBatch(c.txs, c.maxSize)
+			var sizes []int
+			var all [][]byte
+			for _, g := range groups {
+				sizes = append(sizes, len(g))
+				all = append(all, g...)
+				if len(g) > 1 {
+					assert.LessOrEqual(t, len(encodeTxBatch(g)), c.maxSize)
+				}
+			}
+			assert.Equal(t, c.expected, sizes)
+			assert.Equal(t, c.txs, all)
+		})
+	}
+}
+
+func TestTxBatchRejection(t *testing.T) {
+	cases := []struct {
+		name string
+		data []byte
+	}{
+		{"truncated length", []byte{0x80}},
+		{"truncated tx", []byte{5, 1, 2, 3}},
+		{"truncated second tx", append(encodeTxBatch([][]byte{[]byte("tx1")}), 10, 1)},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := decodeTxBatch(c.data)
+			assert.ErrorIs(t, err, ErrInvalidTxBatch)
+		})
+	}
+}
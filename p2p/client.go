@@ -2,8 +2,11 @@ package p2p
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/libp2p/go-libp2p"
@@ -13,8 +16,13 @@ import (
 	"github.com/libp2p/go-libp2p-core/peer"
 	discovery "github.com/libp2p/go-libp2p-discovery"
 	dht "github.com/libp2p/go-libp2p-kad-dht"
+	mplex "github.com/libp2p/go-libp2p-mplex"
+	noise "github.com/libp2p/go-libp2p-noise"
 	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	tls "github.com/libp2p/go-libp2p-tls"
+	yamux "github.com/libp2p/go-libp2p-yamux"
 	routedhost "github.com/libp2p/go-libp2p/p2p/host/routed"
+	"github.com/libp2p/go-libp2p/p2p/protocol/ping"
 	"github.com/multiformats/go-multiaddr"
 	"go.uber.org/multierr"
 
@@ -32,15 +40,57 @@ const (
 
 	// txTopicSuffix is added after namespace to create pubsub topic for TX gossiping.
 	txTopicSuffix = "-tx"
+
+	// maxMessageSize is the maximum size of pubsub message; larger messages are rejected by receivers.
+	maxMessageSize = pubsub.DefaultMaxMessageSize
+	// messageOverhead is an upper bound of size of pubsub message fields other than data (topic, sender, sequence
+	// number and signature).
+	messageOverhead = 1024
+	// MaxPayloadSize is the maximum size of gossiped message data, including envelope.
+	MaxPayloadSize = maxMessageSize - messageOverhead
+
+	// maxInflightPublishes limits the number of publishes waiting for pubsub, including publishes that timed out.
+	maxInflightPublishes = 16
 )
 
+// securityTransports maps names of supported security transports (see config.P2PConfig.Security) to libp2p options.
+var securityTransports = map[string]libp2p.Option{
+	config.SecurityNoise: libp2p.Security(noise.ID, noise.New),
+	config.SecurityTLS:   libp2p.Security(tls.ID, tls.New),
+}
+
+// muxers maps names of supported stream multiplexers (see config.P2PConfig.Muxers) to libp2p options.
+var muxers = map[string]libp2p.Option{
+	config.MuxerYamux: libp2p.Muxer("/yamux/1.0.0", yamux.DefaultTransport),
+	config.MuxerMplex: libp2p.Muxer("/mplex/6.7.0", mplex.DefaultTransport),
+}
+
 // TODO(tzdybal): refactor. This is only a stub.
 type Tx struct {
 	Data []byte
 	From peer.ID
+	// TTL is the number of times transaction can still be relayed, including relaying by receiving node.
+	TTL uint8
 }
+
+// TxHandler is called for every transaction received from peers. It's called during pubsub message validation, so
+// blocking handler delays processing of further messages.
 type TxHandler func(*Tx)
 
+// TxRouter maps transaction to gossip lane. Empty string denotes default lane.
+type TxRouter func(tx []byte) string
+
+// ValidateBasic performs basic, stateless checks of the transaction message.
+func (tx *Tx) ValidateBasic() error {
+	if len(tx.Data) == 0 {
+		return ErrEmptyTx
+	}
+	if err := tx.From.Validate(); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidSender, err)
+	}
+	return nil
+}
+
 // Client is a P2P client, implemented with libp2p.
 //
 // Initially, client connects to predefined seed nodes (aka bootnodes, bootstrap nodes).
@@ -50,14 +100,31 @@ type Client struct {
 	conf    config.P2PConfig
 	chainID string
 	privKey crypto.PrivKey
-
-	host host.Host
-	dht  *dht.IpfsDHT
-	disc *discovery.RoutingDiscovery
-
-	txTopic   *pubsub.Topic
-	txSub     *pubsub.Subscription
-	txHandler TxHandler
+	// genesisHash is verified during handshake and separates networks of chains with the same chain ID
+	genesisHash [32]byte
+
+	host    host.Host
+	limiter *limitedHost
+	// gate refuses connections with peers from different network (see handshake)
+	gate *peerGate
+	// pingFailures is a number of peers disconnected because of ping failure; accessed atomically
+	pingFailures uint64
+	seeds        []peer.AddrInfo
+	dht          *dht.IpfsDHT
+	disc         *discovery.RoutingDiscovery
+
+	// txTopics and txSubs are indexed by lane name
+	txTopics map[string]*pubsub.Topic
+	txSubs   map[string]*pubsub.Subscription
+	// handlerMtx protects txHandler and txRouter, which can be set while client is running
+	handlerMtx sync.RWMutex
+	txHandler  TxHandler
+	txRouter   TxRouter
+
+	// topicPublish publishes data to pubsub topic; it's replaced in tests
+	topicPublish func(ctx context.Context, topic *pubsub.Topic, data []byte) error
+	// inflightPublishes is a semaphore limiting the number of goroutines waiting for pubsub
+	inflightPublishes chan struct{}
 
 	// cancel is used to cancel context passed to libp2p functions
 	// it's required because of discovery.Advertise call
@@ -68,7 +135,8 @@ type Client struct {
 
 // NewClient creates new Client object.
 //
-// Basic checks on parameters are done, and default parameters are provided for unset-configuration
+// Basic checks on parameters are done, and default parameters are provided for unset-configuration.
+// chainID has to uniquely identify ORU network, together with genesis hash (see SetGenesisHash).
 // TODO(tzdybal): consider passing entire config, not just P2P config, to reduce number of arguments
 func NewClient(conf config.P2PConfig, privKey crypto.PrivKey, chainID string, logger log.Logger) (*Client, error) {
 	if privKey == nil {
@@ -77,11 +145,45 @@ func NewClient(conf config.P2PConfig, privKey crypto.PrivKey, chainID string, lo
 	if conf.ListenAddress == "" {
 		conf.ListenAddress = config.DefaultListenAddress
 	}
+	if conf.Security == "" {
+		conf.Security = config.DefaultSecurity
+	}
+	if _, ok := securityTransports[conf.Security]; !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedSecurity, conf.Security)
+	}
+	if len(conf.Muxers) == 0 {
+		conf.Muxers = config.DefaultMuxers
+	}
+	for _, muxer := range conf.Muxers {
+		if _, ok := muxers[muxer]; !ok {
+			return nil, fmt.Errorf("%w: %q", ErrUnsupportedMuxer, muxer)
+		}
+	}
+	if conf.MaxInboundStreams <= 0 {
+		conf.MaxInboundStreams = config.DefaultMaxInboundStreams
+	}
+	if conf.PingInterval == 0 {
+		conf.PingInterval = config.DefaultPingInterval
+	}
+	if conf.PingTimeout == 0 {
+		conf.PingTimeout = config.DefaultPingTimeout
+	}
+	if conf.GossipTTL == 0 {
+		conf.GossipTTL = config.DefaultGossipTTL
+	}
+	if conf.PublishTimeout == 0 {
+		conf.PublishTimeout = config.DefaultPublishTimeout
+	}
 	return &Client{
 		conf:    conf,
 		privKey: privKey,
 		chainID: chainID,
+		gate:    newPeerGate(),
 		logger:  logger,
+		topicPublish: func(ctx context.Context, topic *pubsub.Topic, data []byte) error {
+			return topic.Publish(ctx, data)
+		},
+		inflightPublishes: make(chan struct{}, maxInflightPublishes),
 	}, nil
 }
 
@@ -104,11 +206,14 @@ func (c *Client) Start(ctx context.Context) error {
 }
 
 func (c *Client) startWithHost(ctx context.Context, h host.Host) error {
-	c.host = h
+	c.limiter = newLimitedHost(h, c.conf.MaxInboundStreams, c.logger)
+	c.host = c.limiter
 	for _, a := range c.host.Addrs() {
 		c.logger.Info("listening on", "address", fmt.Sprintf("%s/p2p/%s", a, c.host.ID()))
 	}
 
+	c.setupHandshake(ctx)
+
 	c.logger.Debug("setting up gossiping")
 	err := c.setupGossiping(ctx)
 	if err != nil {
@@ -127,6 +232,10 @@ func (c *Client) startWithHost(ctx context.Context, h host.Host) error {
 		return err
 	}
 
+	// respond to pings of other peers (ping service is not enabled in all hosts)
+	ping.NewPingService(c.host)
+	go c.keepAlive(ctx)
+
 	return nil
 }
 
@@ -134,8 +243,12 @@ func (c *Client) startWithHost(ctx context.Context, h host.Host) error {
 func (c *Client) Close() error {
 	c.cancel()
 
+	var err error
+	for _, topic := range c.txTopics {
+		err = multierr.Append(err, topic.Close())
+	}
 	return multierr.Combine(
-		c.txTopic.Close(),
+		err,
 		c.dht.Close(),
 		c.host.Close(),
 	)
@@ -143,13 +256,170 @@ func (c *Client) Close() error {
 
 func (c *Client) GossipTx(ctx context.Context, tx []byte) error {
 	c.logger.Debug("Gossiping TX", "len", len(tx))
-	return c.txTopic.Publish(ctx, tx)
+	topic, err := c.getLaneTopic(tx)
+	if err != nil {
+		return err
+	}
+	data, err := NewEnvelope(MsgTx, tx).WithTTL(c.conf.GossipTTL).MarshalBinary()
+	if err != nil {
+		return err
+	}
+	return c.publish(ctx, topic, data)
+}
+
+// GossipTxs gossips multiple transactions. Transactions routed to the same lane are sent in a single message, unless
+// it would exceed MaxPayloadSize. If some transactions are not gossiped, *TxGossipError is returned.
+func (c *Client) GossipTxs(ctx context.Context, txs [][]byte) error {
+	return c.RelayTxs(ctx, txs, c.conf.GossipTTL)
+}
+
+// RelayTxs gossips transactions received from peers, with given TTL. It should be lower than TTL of received
+// transactions; transactions can't be relayed with TTL 0 (ErrExpiredTTL). Transactions routed to the same lane are
+// sent in a single message, unless it would exceed MaxPayloadSize. Failure to publish a message doesn't prevent
+// publishing of other messages; if some transactions are not gossiped, *TxGossipError is returned.
+func (c *Client) RelayTxs(ctx context.Context, txs [][]byte, ttl uint8) error {
+	c.logger.Debug("Gossiping TXs", "txs", len(txs), "ttl", ttl)
+	var gossipErr TxGossipError
+	var topics []*pubsub.Topic
+	lanes := make(map[*pubsub.Topic][][]byte)
+	for _, tx := range txs {
+		topic, err := c.getLaneTopic(tx)
+		if err != nil {
+			gossipErr.add([][]byte{tx}, err)
+			continue
+		}
+		if _, ok := lanes[topic]; !ok {
+			topics = append(topics, topic)
+		}
+		lanes[topic] = append(lanes[topic], tx)
+	}
+	for _, topic := range topics {
+		for _, msgTxs := range splitTxBatch(lanes[topic], MaxPayloadSize-envelopeHeaderSize) {
+			if err := c.publishTxs(ctx, topic, msgTxs, ttl); err != nil {
+				gossipErr.add(msgTxs, err)
+			}
+		}
+	}
+	if len(gossipErr.Failed) == 0 {
+		return nil
+	}
+	return &gossipErr
+}
+
+// publishTxs publishes transactions to topic in a single message.
+func (c *Client) publishTxs(ctx context.Context, topic *pubsub.Topic, txs [][]byte, ttl uint8) error {
+	if ttl == 0 {
+		return ErrExpiredTTL
+	}
+	env := NewEnvelope(MsgTx, txs[0])
+	if len(txs) > 1 {
+		env = NewEnvelope(MsgTxBatch, encodeTxBatch(txs))
+	}
+	data, err := env.WithTTL(ttl).MarshalBinary()
+	if err != nil {
+		return err
+	}
+	return c.publish(ctx, topic, data)
+}
+
+// publish publishes data to topic. ErrMessageTooLarge is returned if data exceeds MaxPayloadSize. If ConfirmGossip
+// is set, ErrNoPeers is returned when topic has no peers.
+//
+// Pubsub doesn't respect context while waiting for message to be accepted, so publishing is done in separate
+// goroutine; ErrPublishTimeout is returned if it doesn't complete within PublishTimeout. Goroutine of timed out
+// publish keeps waiting for pubsub, so at most maxInflightPublishes publishes can be pending; when limit is reached,
+// publish waits for a free slot, and times out as well if there is none.
+func (c *Client) publish(ctx context.Context, topic *pubsub.Topic, data []byte) error {
+	if len(data) > MaxPayloadSize {
+		return fmt.Errorf("%w: %d bytes (max: %d)", ErrMessageTooLarge, len(data), MaxPayloadSize)
+	}
+	if c.conf.ConfirmGossip && len(topic.ListPeers()) == 0 {
+		return fmt.Errorf("%w: %q", ErrNoPeers, topic.String())
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.conf.PublishTimeout)
+	defer cancel()
+	select {
+	case c.inflightPublishes <- struct{}{}:
+	case <-ctx.Done():
+		return c.publishError(ctx, topic)
+	}
+	errCh := make(chan error, 1)
+	go func() {
+		defer func() { <-c.inflightPublishes }()
+		errCh <- c.topicPublish(ctx, topic, data)
+	}()
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+		return c.publishError(ctx, topic)
+	}
+}
+
+// publishError returns error of publish aborted because ctx is done.
+func (c *Client) publishError(ctx context.Context, topic *pubsub.Topic) error {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("%w: %q, timeout %s", ErrPublishTimeout, topic.String(), c.conf.PublishTimeout)
+	}
+	return ctx.Err()
+}
+
+// Addrs returns addresses the client is listening on. It returns nil if client is not started.
+func (c *Client) Addrs() []multiaddr.Multiaddr {
+	if c.host == nil {
+		return nil
+	}
+	return c.host.Addrs()
+}
+
+// PingFailures returns number of peers disconnected because they didn't respond to keep-alive ping.
+func (c *Client) PingFailures() uint64 {
+	return atomic.LoadUint64(&c.pingFailures)
+}
+
+// RejectedStreams returns number of inbound streams reset because of MaxInboundStreams limit. It returns 0 if client
+// is not started.
+func (c *Client) RejectedStreams() uint64 {
+	if c.limiter == nil {
+		return 0
+	}
+	return c.limiter.Rejected()
+}
+
+// RejectedPeers returns number of peers disconnected during handshake, because they have the same chain ID, but
+// different genesis.
+func (c *Client) RejectedPeers() uint64 {
+	return uint64(c.gate.numRejected())
+}
+
+// SetGenesisHash sets hash of genesis document of the chain. It has to be called before Start.
+//
+// Genesis hash is included in peer discovery namespace and gossip topics, so nodes with the same chain ID, but
+// different genesis documents don't find each other. Peers connected anyway (for example, configured as seeds) are
+// rejected during handshake.
+func (c *Client) SetGenesisHash(hash [32]byte) {
+	c.genesisHash = hash
 }
 
 func (c *Client) SetTxHandler(handler TxHandler) {
+	c.handlerMtx.Lock()
+	defer c.handlerMtx.Unlock()
+
 	c.txHandler = handler
 }
 
+// SetTxRouter sets function used to select gossip lane for transactions.
+//
+// Router has to return one of the lanes configured in P2PConfig.TxLanes, or empty string for default lane.
+// If router is not set, all transactions are gossiped in default lane.
+func (c *Client) SetTxRouter(router TxRouter) {
+	c.handlerMtx.Lock()
+	defer c.handlerMtx.Unlock()
+
+	c.txRouter = router
+}
+
 func (c *Client) listen(ctx context.Context) (host.Host, error) {
 	var err error
 	maddr, err := multiaddr.NewMultiaddr(c.conf.ListenAddress)
@@ -157,7 +427,16 @@ func (c *Client) listen(ctx context.Context) (host.Host, error) {
 		return nil, err
 	}
 
-	host, err := libp2p.New(ctx, libp2p.ListenAddrs(maddr), libp2p.Identity(c.privKey))
+	options := []libp2p.Option{
+		libp2p.ListenAddrs(maddr),
+		libp2p.Identity(c.privKey),
+		libp2p.ConnectionGater(c.gate),
+		securityTransports[c.conf.Security],
+	}
+	for _, muxer := range c.conf.Muxers {
+		options = append(options, muxers[muxer])
+	}
+	host, err := libp2p.New(ctx, options...)
 	if err != nil {
 		return nil, err
 	}
@@ -167,6 +446,7 @@ func (c *Client) listen(ctx context.Context) (host.Host, error) {
 
 func (c *Client) setupDHT(ctx context.Context) error {
 	seedNodes := c.getSeedAddrInfo(c.conf.Seeds)
+	c.seeds = seedNodes
 	if len(seedNodes) == 0 {
 		c.logger.Info("no seed nodes - only listening for connections")
 	}
@@ -248,39 +528,95 @@ func (c *Client) tryConnect(ctx context.Context, peer peer.AddrInfo) {
 }
 
 func (c *Client) setupGossiping(ctx context.Context) error {
-	ps, err := pubsub.NewGossipSub(ctx, c.host)
-	if err != nil {
-		return err
-	}
-	txTopic, err := ps.Join(c.getTxTopic())
-	if err != nil {
-		return err
+	var opts []pubsub.Option
+	if c.conf.ConfirmGossip {
+		// publish directly to all subscribed peers, not only to mesh peers (mesh is built in background)
+		opts = append(opts, pubsub.WithFloodPublish(true))
 	}
-	c.txTopic = txTopic
-	txSub, err := txTopic.Subscribe()
+	ps, err := pubsub.NewGossipSub(ctx, c.host, opts...)
 	if err != nil {
 		return err
 	}
-	c.txSub = txSub
+	c.txTopics = make(map[string]*pubsub.Topic)
+	c.txSubs = make(map[string]*pubsub.Subscription)
+	for _, lane := range append([]string{""}, c.conf.TxLanes...) {
+		if _, ok := c.txTopics[lane]; ok {
+			continue
+		}
+		if err := ps.RegisterTopicValidator(c.getTxTopic(lane), c.validateTxMsg); err != nil {
+			return err
+		}
+		txTopic, err := ps.Join(c.getTxTopic(lane))
+		if err != nil {
+			return err
+		}
+		c.txTopics[lane] = txTopic
+		txSub, err := txTopic.Subscribe()
+		if err != nil {
+			return err
+		}
+		c.txSubs[lane] = txSub
 
-	go c.processTxs(ctx)
+		go c.drainTxs(ctx, txSub)
+	}
 
 	return nil
 }
 
-func (c *Client) processTxs(ctx context.Context) {
-	for {
-		msg, err := c.txSub.Next(ctx)
-		if err != nil {
-			c.logger.Error("failed to read transaction", "error", err)
-			return
-		}
-		if msg.GetFrom() == c.host.ID() {
-			continue
+// validateTxMsg is a pubsub validator of transaction messages.
+//
+// Messages that can't be decoded, including messages with exhausted TTL, are rejected, so they are neither delivered
+// nor forwarded by pubsub, and peers sending them are penalized.
+//
+// Messages published by self are accepted, so pubsub sends them to peers. Messages received from peers are passed to
+// tx handler and ignored, so that pubsub never forwards them on its own: propagation of transaction is limited to
+// nodes explicitly relaying it with decremented TTL (see RelayTxs).
+func (c *Client) validateTxMsg(ctx context.Context, from peer.ID, msg *pubsub.Message) pubsub.ValidationResult {
+	if c.gate.isRejected(from) {
+		// peer can forward messages before handshake disconnects it
+		return pubsub.ValidationIgnore
+	}
+	var env Envelope
+	if err := env.UnmarshalBinary(msg.Data); err != nil {
+		c.logger.Debug("rejected gossip message", "from", from, "error", err)
+		return pubsub.ValidationReject
+	}
+	if from == c.host.ID() {
+		return pubsub.ValidationAccept
+	}
+
+	var txs [][]byte
+	var err error
+	switch env.Type {
+	case MsgTx:
+		txs = [][]byte{env.Payload}
+	case MsgTxBatch:
+		txs, err = decodeTxBatch(env.Payload)
+	default:
+		err = fmt.Errorf("%w: %d", ErrUnexpectedMessage, env.Type)
+	}
+	if err != nil {
+		c.logger.Debug("rejected gossip message", "from", from, "error", err)
+		return pubsub.ValidationReject
+	}
+
+	c.handlerMtx.RLock()
+	handler := c.txHandler
+	c.handlerMtx.RUnlock()
+	if handler != nil {
+		for _, tx := range txs {
+			handler(&Tx{Data: tx, From: msg.GetFrom(), TTL: env.TTL})
 		}
+	}
+	return pubsub.ValidationIgnore
+}
 
-		if c.txHandler != nil {
-			c.txHandler(&Tx{Data: msg.Data, From: msg.GetFrom()})
+// drainTxs discards messages delivered to sub. Only messages published by self are delivered (see validateTxMsg);
+// subscription is required only to join the topic mesh.
+func (c *Client) drainTxs(ctx context.Context, sub *pubsub.Subscription) {
+	for {
+		if _, err := sub.Next(ctx); err != nil {
+			return
 		}
 	}
 }
@@ -309,12 +645,36 @@ func (c *Client) getSeedAddrInfo(seedStr string) []peer.AddrInfo {
 
 // getNamespace returns unique string identifying ORU network.
 //
-// It is used to advertise/find peers in libp2p DHT.
-// For now, chainID is used.
+// It is used to advertise/find peers in libp2p DHT, and as a prefix of gossip topics.
+// Chain ID is combined with genesis hash, if it's set.
 func (c *Client) getNamespace() string {
-	return c.chainID
+	if c.genesisHash == [32]byte{} {
+		return c.chainID
+	}
+	return fmt.Sprintf("%s-%X", c.chainID, c.genesisHash[:8])
+}
+
+// getLaneTopic returns pubsub topic of the lane selected for tx by router.
+func (c *Client) getLaneTopic(tx []byte) (*pubsub.Topic, error) {
+	c.handlerMtx.RLock()
+	router := c.txRouter
+	c.handlerMtx.RUnlock()
+
+	var lane string
+	if router != nil {
+		lane = router(tx)
+	}
+	topic, ok := c.txTopics[lane]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownLane, lane)
+	}
+	return topic, nil
 }
 
-func (c *Client) getTxTopic() string {
-	return c.getNamespace() + txTopicSuffix
+// getTxTopic returns pubsub topic for TX gossiping in given lane.
+func (c *Client) getTxTopic(lane string) string {
+	if lane == "" {
+		return c.getNamespace() + txTopicSuffix
+	}
+	return c.getNamespace() + txTopicSuffix + "-" + lane
 }
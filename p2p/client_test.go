@@ -3,14 +3,20 @@ package p2p
 import (
 	"context"
 	"crypto/rand"
+	"errors"
 	"fmt"
+	"strings"
 	"sync"
 	"testing"
 	"time"
 
 	"github.com/ipfs/go-log"
+	tmlog "github.com/lazyledger/lazyledger-core/libs/log"
 	"github.com/libp2p/go-libp2p-core/crypto"
 	"github.com/libp2p/go-libp2p-core/peer"
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	pb "github.com/libp2p/go-libp2p-pubsub/pb"
+	mocknet "github.com/libp2p/go-libp2p/p2p/net/mock"
 	"github.com/multiformats/go-multiaddr"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -161,6 +167,361 @@ func TestGossiping(t *testing.T) {
 	wg.Wait()
 }
 
+func TestGossipingLanes(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	logger := &TestLogger{t}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	lanes := []string{"a", "b"}
+	clients := startTestNetwork(ctx, t, 2, map[int]hostDescr{
+		0: hostDescr{conns: []int{}, chainID: "1", txLanes: lanes, realKey: true},
+		1: hostDescr{conns: []int{0}, chainID: "1", txLanes: lanes, realKey: true},
+	}, logger)
+	clients.WaitForDHT()
+
+	router := func(tx []byte) string {
+		return strings.SplitN(string(tx), ":", 2)[0]
+	}
+	clients[1].SetTxRouter(router)
+
+	// additional subscriptions, to check which topic was used; messages published by self are delivered locally
+	subA, err := clients[1].txTopics["a"].Subscribe()
+	require.NoError(err)
+	subB, err := clients[1].txTopics["b"].Subscribe()
+	require.NoError(err)
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	clients[0].SetTxHandler(func(*Tx) { wg.Done() })
+
+	time.Sleep(1 * time.Second)
+
+	assert.NoError(clients[1].GossipTx(ctx, []byte("a:tx1")))
+	assert.NoError(clients[1].GossipTx(ctx, []byte("b:tx2")))
+	assert.ErrorIs(clients[1].GossipTx(ctx, []byte("c:tx3")), ErrUnknownLane)
+
+	for sub, expected := range map[*pubsub.Subscription]string{subA: "a:tx1", subB: "b:tx2"} {
+		msg, err := sub.Next(ctx)
+		require.NoError(err)
+		var env Envelope
+		require.NoError(env.UnmarshalBinary(msg.Data))
+		assert.Equal(expected, string(env.Payload))
+	}
+
+	// transactions from all lanes are passed to handler
+	wg.Wait()
+}
+
+func TestGossipingLargeBatch(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	logger := &TestLogger{t}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	clients := startTestNetwork(ctx, t, 2, map[int]hostDescr{
+		0: hostDescr{conns: []int{}, chainID: "1", realKey: true},
+		1: hostDescr{conns: []int{0}, chainID: "1", realKey: true},
+	}, logger)
+	clients.WaitForDHT()
+
+	received := make(chan *Tx, 10)
+	clients[0].SetTxHandler(func(tx *Tx) { received <- tx })
+	time.Sleep(1 * time.Second)
+
+	// transactions don't fit in a single message
+	txs := make([][]byte, 3)
+	for i := range txs {
+		txs[i] = append([]byte{byte(i)}, make([]byte, MaxPayloadSize/2)...)
+	}
+	require.NoError(clients[1].GossipTxs(ctx, txs))
+	for range txs {
+		select {
+		case tx := <-received:
+			assert.Contains(txs, tx.Data)
+		case <-time.After(5 * time.Second):
+			t.Fatal("timeout while waiting for transactions")
+		}
+	}
+}
+
+func TestPartialLaneFailure(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	logger := &TestLogger{t}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// nobody else subscribes to lane "b"
+	clients := startTestNetwork(ctx, t, 2, map[int]hostDescr{
+		0: hostDescr{conns: []int{}, chainID: "1", txLanes: []string{"a"}, realKey: true},
+		1: hostDescr{conns: []int{0}, chainID: "1", txLanes: []string{"a", "b"}, realKey: true},
+	}, logger)
+	clients.WaitForDHT()
+	clients[1].conf.ConfirmGossip = true
+	clients[1].SetTxRouter(func(tx []byte) string {
+		return strings.SplitN(string(tx), ":", 2)[0]
+	})
+
+	received := make(chan *Tx, 10)
+	clients[0].SetTxHandler(func(tx *Tx) { received <- tx })
+	time.Sleep(1 * time.Second)
+
+	err := clients[1].GossipTxs(ctx, [][]byte{[]byte("a:tx1"), []byte("b:tx2"), []byte("c:tx3"), []byte("a:tx4")})
+	var gossipErr *TxGossipError
+	require.True(errors.As(err, &gossipErr), err)
+	assert.ErrorIs(err, ErrNoPeers)
+	assert.ErrorIs(err, ErrUnknownLane)
+	require.Len(gossipErr.Failed, 2)
+	assert.Equal([][]byte{[]byte("c:tx3")}, gossipErr.Failed[0].Txs)
+	assert.ErrorIs(gossipErr.Failed[0].Err, ErrUnknownLane)
+	assert.Equal([][]byte{[]byte("b:tx2")}, gossipErr.Failed[1].Txs)
+	assert.ErrorIs(gossipErr.Failed[1].Err, ErrNoPeers)
+
+	// transactions from lane with peers are delivered
+	for _, expected := range []string{"a:tx1", "a:tx4"} {
+		select {
+		case tx := <-received:
+			assert.Equal(expected, string(tx.Data))
+		case <-time.After(5 * time.Second):
+			t.Fatal("timeout while waiting for transactions")
+		}
+	}
+}
+
+func TestGossipTTL(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	logger := &TestLogger{t}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// network connections topology: 0<->1<->2<->3<->4
+	clients := startTestNetwork(ctx, t, 5, map[int]hostDescr{
+		0: hostDescr{conns: []int{}, chainID: "1", realKey: true, isolated: true},
+		1: hostDescr{conns: []int{0}, chainID: "1", realKey: true, isolated: true},
+		2: hostDescr{conns: []int{1}, chainID: "1", realKey: true, isolated: true},
+		3: hostDescr{conns: []int{2}, chainID: "1", realKey: true, isolated: true},
+		4: hostDescr{conns: []int{3}, chainID: "1", realKey: true, isolated: true},
+	}, logger)
+	clients.WaitForDHT()
+
+	// every client relays every received message with decremented TTL (worst case, without deduplication)
+	var mtx sync.Mutex
+	received := make([]map[uint8]int, len(clients))
+	for i, c := range clients {
+		i, c := i, c
+		received[i] = make(map[uint8]int)
+		c.SetTxHandler(func(tx *Tx) {
+			mtx.Lock()
+			received[i][tx.TTL]++
+			mtx.Unlock()
+			if tx.TTL > 1 {
+				go func() { _ = c.RelayTxs(ctx, [][]byte{tx.Data}, tx.TTL-1) }()
+			}
+		})
+	}
+
+	time.Sleep(1 * time.Second)
+
+	// transactions with exhausted TTL are not published
+	err := clients[0].RelayTxs(ctx, [][]byte{[]byte("expired")}, 0)
+	assert.True(errors.Is(err, ErrExpiredTTL))
+
+	require.NoError(clients[0].RelayTxs(ctx, [][]byte{[]byte("tx")}, 3))
+
+	// transaction reaches 3 hops away, with TTL decremented on every hop
+	require.Eventually(func() bool {
+		mtx.Lock()
+		defer mtx.Unlock()
+		return received[3][1] > 0
+	}, 10*time.Second, 100*time.Millisecond)
+	// give pubsub time to deliver anything that would go past the limit
+	time.Sleep(1 * time.Second)
+
+	mtx.Lock()
+	defer mtx.Unlock()
+	// relays of client 1 (TTL 2) reach clients 0 and 2, whose relays (TTL 1) reach clients 1 and 3
+	assert.Equal(map[uint8]int{2: 1}, received[0])
+	assert.Equal(map[uint8]int{3: 1, 1: 2}, received[1])
+	assert.Equal(map[uint8]int{2: 1}, received[2])
+	assert.Equal(map[uint8]int{1: 1}, received[3])
+	assert.Empty(received[4])
+}
+
+func TestValidateTxMsg(t *testing.T) {
+	valid, err := NewEnvelope(MsgTx, []byte("tx")).WithTTL(2).MarshalBinary()
+	require.NoError(t, err)
+	expired, err := NewEnvelope(MsgTx, []byte("tx")).WithTTL(0).MarshalBinary()
+	require.NoError(t, err)
+
+	cases := []struct {
+		name     string
+		data     []byte
+		expected pubsub.ValidationResult
+	}{
+		{"valid", valid, pubsub.ValidationIgnore},
+		{"expired TTL", expired, pubsub.ValidationReject},
+		{"malformed", []byte{1}, pubsub.ValidationReject},
+	}
+
+	host, err := mocknet.New(context.Background()).GenPeer()
+	require.NoError(t, err)
+	client := &Client{host: host, gate: newPeerGate(), logger: &TestLogger{t}}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			msg := &pubsub.Message{Message: &pb.Message{Data: c.data}}
+			assert.Equal(t, c.expected, client.validateTxMsg(context.Background(), peer.ID("peer"), msg))
+		})
+	}
+
+	t.Run("published by self", func(t *testing.T) {
+		msg := &pubsub.Message{Message: &pb.Message{Data: valid}}
+		assert.Equal(t, pubsub.ValidationAccept, client.validateTxMsg(context.Background(), host.ID(), msg))
+	})
+
+	t.Run("rejected peer", func(t *testing.T) {
+		client.gate.reject(peer.ID("rejected"))
+		msg := &pubsub.Message{Message: &pb.Message{Data: valid}}
+		assert.Equal(t, pubsub.ValidationIgnore, client.validateTxMsg(context.Background(), peer.ID("rejected"), msg))
+	})
+}
+
+func TestConfirmGossip(t *testing.T) {
+	for _, confirm := range []bool{false, true} {
+		t.Run(fmt.Sprint(confirm), func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			privKey, _, _ := crypto.GenerateEd25519Key(rand.Reader)
+			client, err := NewClient(config.P2PConfig{ConfirmGossip: confirm}, privKey, "TestChain", tmlog.TestingLogger())
+			require.NoError(err)
+			require.NoError(client.Start(context.Background()))
+			defer client.Close()
+
+			err = client.GossipTx(context.Background(), []byte("tx"))
+			if confirm {
+				assert.True(errors.Is(err, ErrNoPeers), err)
+			} else {
+				assert.NoError(err)
+			}
+		})
+	}
+}
+
+func TestPublishTimeout(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	privKey, _, _ := crypto.GenerateEd25519Key(rand.Reader)
+	conf := config.P2PConfig{PublishTimeout: 100 * time.Millisecond}
+	client, err := NewClient(conf, privKey, "TestChain", tmlog.TestingLogger())
+	require.NoError(err)
+	require.NoError(client.Start(context.Background()))
+	defer client.Close()
+
+	unblock := make(chan struct{})
+	defer close(unblock)
+	client.topicPublish = func(context.Context, *pubsub.Topic, []byte) error {
+		<-unblock
+		return nil
+	}
+
+	start := time.Now()
+	err = client.GossipTx(context.Background(), []byte("tx"))
+	assert.True(errors.Is(err, ErrPublishTimeout), err)
+	assert.Less(int64(time.Since(start)), int64(10*conf.PublishTimeout))
+
+	err = client.GossipTxs(context.Background(), [][]byte{[]byte("tx1"), []byte("tx2")})
+	assert.True(errors.Is(err, ErrPublishTimeout), err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = client.GossipTx(ctx, []byte("tx"))
+	assert.True(errors.Is(err, context.Canceled), err)
+
+	// publishes blocked in pubsub are limited
+	for i := 0; i < 2*maxInflightPublishes; i++ {
+		err = client.GossipTx(context.Background(), []byte("tx"))
+		assert.True(errors.Is(err, ErrPublishTimeout), err)
+	}
+	assert.Len(client.inflightPublishes, maxInflightPublishes)
+}
+
+func TestMessageTooLarge(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	privKey, _, _ := crypto.GenerateEd25519Key(rand.Reader)
+	client, err := NewClient(config.P2PConfig{}, privKey, "TestChain", tmlog.TestingLogger())
+	require.NoError(err)
+	require.NoError(client.Start(context.Background()))
+	defer client.Close()
+
+	err = client.GossipTx(context.Background(), make([]byte, MaxPayloadSize))
+	assert.True(errors.Is(err, ErrMessageTooLarge), err)
+	assert.NoError(client.GossipTx(context.Background(), make([]byte, MaxPayloadSize-envelopeHeaderSize)))
+}
+
+func TestSecurityTransports(t *testing.T) {
+	cases := []struct {
+		name     string
+		security string
+		muxers   []string
+		other    string
+	}{
+		{"default", "", nil, config.SecurityTLS},
+		{"noise", config.SecurityNoise, nil, config.SecurityTLS},
+		{"tls", config.SecurityTLS, nil, config.SecurityNoise},
+		{"tls, mplex", config.SecurityTLS, []string{config.MuxerMplex}, config.SecurityNoise},
+		{"noise, yamux", config.SecurityNoise, []string{config.MuxerYamux}, config.SecurityTLS},
+	}
+
+	startClient := func(t *testing.T, security string, muxers []string) *Client {
+		t.Helper()
+		privKey, _, _ := crypto.GenerateEd25519Key(rand.Reader)
+		conf := config.P2PConfig{ListenAddress: "/ip4/127.0.0.1/tcp/0", Security: security, Muxers: muxers}
+		client, err := NewClient(conf, privKey, "TestChain", tmlog.TestingLogger())
+		require.NoError(t, err)
+		require.NoError(t, client.Start(context.Background()))
+		t.Cleanup(func() { _ = client.Close() })
+		return client
+	}
+	connect := func(from, to *Client) error {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		return from.host.Connect(ctx, peer.AddrInfo{ID: to.host.ID(), Addrs: to.Addrs()})
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			client := startClient(t, c.security, c.muxers)
+			// peers using the same security transport can connect
+			assert.NoError(connect(startClient(t, c.security, c.muxers), client))
+			// peers using different security transport can't connect
+			assert.Error(connect(startClient(t, c.other, nil), client))
+		})
+	}
+
+	t.Run("unsupported", func(t *testing.T) {
+		assert := assert.New(t)
+
+		privKey, _, _ := crypto.GenerateEd25519Key(rand.Reader)
+		_, err := NewClient(config.P2PConfig{Security: "secio"}, privKey, "TestChain", tmlog.TestingLogger())
+		assert.ErrorIs(err, ErrUnsupportedSecurity)
+		_, err = NewClient(config.P2PConfig{Muxers: []string{config.MuxerYamux, "spdy"}}, privKey, "TestChain", tmlog.TestingLogger())
+		assert.ErrorIs(err, ErrUnsupportedMuxer)
+	})
+}
+
 func TestSeedStringParsing(t *testing.T) {
 	t.Parallel()
 
@@ -212,3 +573,31 @@ func TestSeedStringParsing(t *testing.T) {
 		})
 	}
 }
+
+func TestTxValidateBasic(t *testing.T) {
+	privKey, _, _ := crypto.GenerateEd25519Key(rand.Reader)
+	pid, err := peer.IDFromPrivateKey(privKey)
+	require.NoError(t, err)
+
+	cases := []struct {
+		name        string
+		tx          *Tx
+		expectedErr error
+	}{
+		{"valid", &Tx{Data: []byte("tx"), From: pid}, nil},
+		{"empty data", &Tx{Data: []byte{}, From: pid}, ErrEmptyTx},
+		{"nil data", &Tx{From: pid}, ErrEmptyTx},
+		{"empty from", &Tx{Data: []byte("tx")}, ErrInvalidSender},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := c.tx.ValidateBasic()
+			if c.expectedErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, c.expectedErr)
+			}
+		})
+	}
+}
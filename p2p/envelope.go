@@ -0,0 +1,88 @@
+package p2p
+
+import "fmt"
+
+// ProtocolVersion is the version of p2p wire format. It's increased on every incompatible change.
+const ProtocolVersion uint8 = 2
+
+// envelopeHeaderSize is the size of version, message type and TTL prefix.
+const envelopeHeaderSize = 3
+
+// MessageType identifies the type of payload carried in Envelope.
+type MessageType uint8
+
+const (
+	MsgTx MessageType = iota + 1
+	MsgBlock
+	MsgChunk
+	MsgSyncRequest
+	MsgSyncResponse
+	MsgTxBatch
+)
+
+func (t MessageType) valid() bool {
+	return t >= MsgTx && t <= MsgTxBatch
+}
+
+// Envelope wraps every payload sent over p2p network.
+//
+// On the wire, envelope is encoded as: version (1 byte) | message type (1 byte) | TTL (1 byte) | payload.
+type Envelope struct {
+	Version uint8
+	Type    MessageType
+	// TTL is the number of times payload can still be relayed. Payload received with TTL 1 is not relayed.
+	TTL     uint8
+	Payload []byte
+}
+
+// NewEnvelope returns envelope of given type, with current protocol version and TTL 1.
+func NewEnvelope(msgType MessageType, payload []byte) *Envelope {
+	return &Envelope{
+		Version: ProtocolVersion,
+		Type:    msgType,
+		TTL:     1,
+		Payload: payload,
+	}
+}
+
+// WithTTL sets the TTL of envelope and returns it.
+func (e *Envelope) WithTTL(ttl uint8) *Envelope {
+	e.TTL = ttl
+	return e
+}
+
+// MarshalBinary encodes envelope into binary form.
+func (e *Envelope) MarshalBinary() ([]byte, error) {
+	buf := make([]byte, envelopeHeaderSize+len(e.Payload))
+	buf[0] = e.Version
+	buf[1] = byte(e.Type)
+	buf[2] = e.TTL
+	copy(buf[envelopeHeaderSize:], e.Payload)
+	return buf, nil
+}
+
+// UnmarshalBinary decodes binary form data into envelope.
+//
+// Envelopes with protocol version different than ProtocolVersion, unknown message type or TTL 0 are rejected.
+func (e *Envelope) UnmarshalBinary(data []byte) error {
+	if len(data) < envelopeHeaderSize {
+		return fmt.Errorf("%w: %d bytes", ErrEnvelopeTooShort, len(data))
+	}
+	if data[0] != ProtocolVersion {
+		return fmt.Errorf("%w: %d (supported: %d)", ErrUnknownVersion, data[0], ProtocolVersion)
+	}
+	msgType := MessageType(data[1])
+	if !msgType.valid() {
+		return fmt.Errorf("%w: %d", ErrUnknownMessageType, msgType)
+	}
+
+	if data[2] == 0 {
+		return ErrExpiredTTL
+	}
+
+	e.Version = data[0]
+	e.Type = msgType
+	e.TTL = data[2]
+	e.Payload = data[envelopeHeaderSize:]
+	return nil
+}
@@ -0,0 +1,66 @@
+package p2p
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnvelopeRoundTrip(t *testing.T) {
+	cases := []struct {
+		name    string
+		msgType MessageType
+		ttl     uint8
+		payload []byte
+	}{
+		{"tx", MsgTx, 1, []byte("some tx")},
+		{"tx with ttl", MsgTx, 7, []byte("some tx")},
+		{"block", MsgBlock, 1, []byte{1, 2, 3, 4}},
+		{"chunk", MsgChunk, 1, []byte{0xff}},
+		{"sync request", MsgSyncRequest, 1, []byte{}},
+		{"sync response", MsgSyncResponse, 1, make([]byte, 1024)},
+		{"tx batch", MsgTxBatch, 255, encodeTxBatch([][]byte{[]byte("tx1"), []byte("tx2")})},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			data, err := NewEnvelope(c.msgType, c.payload).WithTTL(c.ttl).MarshalBinary()
+			require.NoError(err)
+
+			var env Envelope
+			require.NoError(env.UnmarshalBinary(data))
+			assert.Equal(ProtocolVersion, env.Version)
+			assert.Equal(c.msgType, env.Type)
+			assert.Equal(c.ttl, env.TTL)
+			assert.Equal(c.payload, env.Payload)
+		})
+	}
+}
+
+func TestEnvelopeRejection(t *testing.T) {
+	cases := []struct {
+		name        string
+		data        []byte
+		expectedErr error
+	}{
+		{"empty", nil, ErrEnvelopeTooShort},
+		{"no type", []byte{ProtocolVersion}, ErrEnvelopeTooShort},
+		{"no TTL", []byte{ProtocolVersion, byte(MsgTx)}, ErrEnvelopeTooShort},
+		{"expired TTL", []byte{ProtocolVersion, byte(MsgTx), 0, 1, 2, 3}, ErrExpiredTTL},
+		{"unknown version", []byte{ProtocolVersion + 1, byte(MsgTx), 1, 2, 3}, ErrUnknownVersion},
+		{"zero version", []byte{0, byte(MsgTx), 1, 2, 3}, ErrUnknownVersion},
+		{"zero type", []byte{ProtocolVersion, 0, 1, 2, 3}, ErrUnknownMessageType},
+		{"unknown type", []byte{ProtocolVersion, byte(MsgTxBatch) + 1, 1, 2, 3}, ErrUnknownMessageType},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var env Envelope
+			assert.ErrorIs(t, env.UnmarshalBinary(c.data), c.expectedErr)
+		})
+	}
+}
@@ -1,7 +1,65 @@
 package p2p
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 var (
 	ErrNoPrivKey = errors.New("private key not provided")
+
+	ErrUnsupportedSecurity = errors.New("unsupported security transport")
+	ErrUnsupportedMuxer    = errors.New("unsupported stream multiplexer")
+
+	ErrEnvelopeTooShort   = errors.New("envelope too short")
+	ErrUnknownVersion     = errors.New("unknown protocol version")
+	ErrUnknownMessageType = errors.New("unknown message type")
+	ErrUnexpectedMessage  = errors.New("unexpected message type")
+	ErrExpiredTTL         = errors.New("message TTL expired")
+	ErrInvalidTxBatch     = errors.New("invalid transaction batch")
+
+	ErrEmptyTx       = errors.New("empty transaction")
+	ErrInvalidSender = errors.New("invalid sender")
+
+	ErrUnknownLane = errors.New("unknown gossip lane")
+	ErrNoPeers     = errors.New("no peers subscribed to gossip lane")
+
+	ErrPublishTimeout  = errors.New("timed out publishing gossip message")
+	ErrMessageTooLarge = errors.New("gossip message too large")
+
+	ErrChainIDTooLong = errors.New("chain ID too long")
 )
+
+// TxGossipError is returned when some of gossiped transactions were not published.
+type TxGossipError struct {
+	// Failed lists transactions that were not published, grouped by message they were supposed to be sent in.
+	Failed []FailedTxs
+}
+
+// FailedTxs are transactions that were not published because of Err.
+type FailedTxs struct {
+	Txs [][]byte
+	Err error
+}
+
+func (e *TxGossipError) add(txs [][]byte, err error) {
+	e.Failed = append(e.Failed, FailedTxs{Txs: txs, Err: err})
+}
+
+func (e *TxGossipError) Error() string {
+	n := 0
+	for _, f := range e.Failed {
+		n += len(f.Txs)
+	}
+	return fmt.Sprintf("failed to gossip %d txs in %d messages, first error: %v", n, len(e.Failed), e.Failed[0].Err)
+}
+
+// Is reports whether any of the publishing errors matches target.
+func (e *TxGossipError) Is(target error) bool {
+	for _, f := range e.Failed {
+		if errors.Is(f.Err, target) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,213 @@
+package p2p
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/control"
+	"github.com/libp2p/go-libp2p-core/host"
+	"github.com/libp2p/go-libp2p-core/network"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/multiformats/go-multiaddr"
+)
+
+// handshakeProtocol is the protocol used to exchange chain ID and genesis hash of connected peers.
+const handshakeProtocol = "/optimint/handshake/1.0.0"
+
+const (
+	handshakeTimeout = 10 * time.Second
+	// maxChainIDLength limits the size of chain ID read from peer
+	maxChainIDLength = 1024
+)
+
+// handshakeMsg identifies the chain of a peer.
+type handshakeMsg struct {
+	chainID     string
+	genesisHash [32]byte
+}
+
+// peerGate refuses connections with peers rejected during handshake (see setupHandshake).
+//
+// It implements connmgr.ConnectionGater, so connections with rejected peers are refused before they're established.
+type peerGate struct {
+	mtx      sync.Mutex
+	rejected map[peer.ID]bool
+}
+
+func newPeerGate() *peerGate {
+	return &peerGate{rejected: make(map[peer.ID]bool)}
+}
+
+// reject refuses all future connections with p. It returns false if p was already rejected.
+func (g *peerGate) reject(p peer.ID) bool {
+	g.mtx.Lock()
+	defer g.mtx.Unlock()
+
+	if g.rejected[p] {
+		return false
+	}
+	g.rejected[p] = true
+	return true
+}
+
+func (g *peerGate) isRejected(p peer.ID) bool {
+	g.mtx.Lock()
+	defer g.mtx.Unlock()
+
+	return g.rejected[p]
+}
+
+// numRejected returns the number of rejected peers.
+func (g *peerGate) numRejected() int {
+	g.mtx.Lock()
+	defer g.mtx.Unlock()
+
+	return len(g.rejected)
+}
+
+func (g *peerGate) InterceptPeerDial(p peer.ID) bool {
+	return !g.isRejected(p)
+}
+
+func (g *peerGate) InterceptAddrDial(p peer.ID, _ multiaddr.Multiaddr) bool {
+	return !g.isRejected(p)
+}
+
+func (g *peerGate) InterceptAccept(network.ConnMultiaddrs) bool {
+	return true
+}
+
+func (g *peerGate) InterceptSecured(_ network.Direction, p peer.ID, _ network.ConnMultiaddrs) bool {
+	return !g.isRejected(p)
+}
+
+func (g *peerGate) InterceptUpgraded(network.Conn) (bool, control.DisconnectReason) {
+	return true, 0
+}
+
+// setupHandshake makes client verify genesis of every peer it connects to.
+//
+// Dialing side opens handshake stream; both sides send their chain ID and genesis hash. Peer with the same chain ID,
+// but different genesis hash is disconnected, and further connections with it are refused. Peers of other chains
+// are not rejected, because DHT is shared by all chains (gossip topics are separated by chain ID and genesis hash).
+// Peers that don't support handshake protocol (for example seed nodes serving only DHT) are not verified.
+func (c *Client) setupHandshake(ctx context.Context) {
+	// c.host is replaced with routed host in setupDHT, while handshakes are already running, so host is captured here
+	h := c.host
+	h.SetStreamHandler(handshakeProtocol, func(s network.Stream) { c.handleHandshake(h, s) })
+	h.Network().Notify(&network.NotifyBundle{
+		ConnectedF: func(_ network.Network, conn network.Conn) {
+			p := conn.RemotePeer()
+			if c.gate.isRejected(p) {
+				// gater is not used by all hosts (e.g. in tests), so rejected peers are disconnected here as well
+				go func() { _ = h.Network().ClosePeer(p) }()
+				return
+			}
+			if conn.Stat().Direction == network.DirOutbound {
+				go c.handshake(ctx, h, p)
+			}
+		},
+	})
+}
+
+// handshake sends chain ID and genesis hash to p and verifies the ones received in response.
+func (c *Client) handshake(ctx context.Context, h host.Host, p peer.ID) {
+	ctx, cancel := context.WithTimeout(ctx, handshakeTimeout)
+	defer cancel()
+
+	s, err := h.NewStream(ctx, p, handshakeProtocol)
+	if err != nil {
+		c.logger.Debug("handshake not performed", "peer", p, "error", err)
+		return
+	}
+	defer s.Close()
+	_ = s.SetDeadline(time.Now().Add(handshakeTimeout))
+
+	if err := writeHandshake(s, c.handshakeMsg()); err != nil {
+		c.logger.Debug("handshake failed", "peer", p, "error", err)
+		_ = s.Reset()
+		return
+	}
+	remote, err := readHandshake(s)
+	if err != nil {
+		c.logger.Debug("handshake failed", "peer", p, "error", err)
+		_ = s.Reset()
+		return
+	}
+	c.verifyHandshake(h, p, remote)
+}
+
+// handleHandshake responds to handshake opened by the peer.
+func (c *Client) handleHandshake(h host.Host, s network.Stream) {
+	defer s.Close()
+	_ = s.SetDeadline(time.Now().Add(handshakeTimeout))
+
+	p := s.Conn().RemotePeer()
+	remote, err := readHandshake(s)
+	if err != nil {
+		c.logger.Debug("handshake failed", "peer", p, "error", err)
+		_ = s.Reset()
+		return
+	}
+	if err := writeHandshake(s, c.handshakeMsg()); err != nil {
+		c.logger.Debug("handshake failed", "peer", p, "error", err)
+	}
+	c.verifyHandshake(h, p, remote)
+}
+
+func (c *Client) handshakeMsg() handshakeMsg {
+	return handshakeMsg{chainID: c.chainID, genesisHash: c.genesisHash}
+}
+
+// verifyHandshake disconnects p and refuses further connections with it, if it's from the same chain, but has
+// different genesis. Genesis is not verified if either side doesn't know its genesis hash.
+func (c *Client) verifyHandshake(h host.Host, p peer.ID, remote handshakeMsg) {
+	if remote.chainID != c.chainID || remote.genesisHash == c.genesisHash ||
+		remote.genesisHash == [32]byte{} || c.genesisHash == [32]byte{} {
+		return
+	}
+	if c.gate.reject(p) {
+		c.logger.Info("rejecting peer with different genesis", "peer", p, "chainID", c.chainID,
+			"genesisHash", fmt.Sprintf("%X", remote.genesisHash), "expected", fmt.Sprintf("%X", c.genesisHash))
+	}
+	if err := h.Network().ClosePeer(p); err != nil {
+		c.logger.Debug("failed to disconnect peer", "peer", p, "error", err)
+	}
+}
+
+func writeHandshake(w io.Writer, msg handshakeMsg) error {
+	if len(msg.chainID) > maxChainIDLength {
+		return fmt.Errorf("%w: %d bytes", ErrChainIDTooLong, len(msg.chainID))
+	}
+	buf := make([]byte, 2+len(msg.chainID)+len(msg.genesisHash))
+	binary.BigEndian.PutUint16(buf, uint16(len(msg.chainID)))
+	copy(buf[2:], msg.chainID)
+	copy(buf[2+len(msg.chainID):], msg.genesisHash[:])
+	_, err := w.Write(buf)
+	return err
+}
+
+func readHandshake(r io.Reader) (handshakeMsg, error) {
+	var msg handshakeMsg
+	var length [2]byte
+	if _, err := io.ReadFull(r, length[:]); err != nil {
+		return msg, err
+	}
+	n := binary.BigEndian.Uint16(length[:])
+	if n > maxChainIDLength {
+		return msg, fmt.Errorf("%w: %d bytes", ErrChainIDTooLong, n)
+	}
+	chainID := make([]byte, n)
+	if _, err := io.ReadFull(r, chainID); err != nil {
+		return msg, err
+	}
+	msg.chainID = string(chainID)
+	if _, err := io.ReadFull(r, msg.genesisHash[:]); err != nil {
+		return msg, err
+	}
+	return msg, nil
+}
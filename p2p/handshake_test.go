@@ -0,0 +1,116 @@
+package p2p
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"testing"
+	"time"
+
+	tmlog "github.com/lazyledger/lazyledger-core/libs/log"
+	"github.com/libp2p/go-libp2p-core/crypto"
+	"github.com/libp2p/go-libp2p-core/network"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lazyledger/optimint/config"
+)
+
+func TestHandshakeEncoding(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	msg := handshakeMsg{chainID: "TestChain", genesisHash: [32]byte{1, 2, 3}}
+	var buf bytes.Buffer
+	require.NoError(writeHandshake(&buf, msg))
+	decoded, err := readHandshake(&buf)
+	require.NoError(err)
+	assert.Equal(msg, decoded)
+
+	_, err = readHandshake(bytes.NewReader([]byte{0xff, 0xff}))
+	assert.ErrorIs(err, ErrChainIDTooLong)
+	assert.ErrorIs(writeHandshake(&buf, handshakeMsg{chainID: string(make([]byte, maxChainIDLength+1))}), ErrChainIDTooLong)
+}
+
+func TestVerifyHandshake(t *testing.T) {
+	local := handshakeMsg{chainID: "TestChain", genesisHash: [32]byte{1}}
+	cases := []struct {
+		name     string
+		local    handshakeMsg
+		remote   handshakeMsg
+		rejected bool
+	}{
+		{"same genesis", local, local, false},
+		{"different genesis", local, handshakeMsg{chainID: "TestChain", genesisHash: [32]byte{2}}, true},
+		{"different chain", local, handshakeMsg{chainID: "OtherChain", genesisHash: [32]byte{2}}, false},
+		{"unknown remote genesis", local, handshakeMsg{chainID: "TestChain"}, false},
+		{"unknown local genesis", handshakeMsg{chainID: "TestChain"}, local, false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			// client outlives subtest (e.g. discovery goroutines), so it can't log with t
+			clients := startTestNetwork(ctx, t, 1, nil, tmlog.NewNopLogger())
+			client := clients[0]
+			client.chainID = c.local.chainID
+			client.genesisHash = c.local.genesisHash
+
+			client.verifyHandshake(client.host, peer.ID("peer"), c.remote)
+			assert.Equal(t, c.rejected, client.gate.isRejected(peer.ID("peer")))
+		})
+	}
+}
+
+func TestHandshakeGenesisMismatch(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	startClient := func(genesisHash [32]byte) *Client {
+		privKey, _, _ := crypto.GenerateEd25519Key(rand.Reader)
+		conf := config.P2PConfig{ListenAddress: "/ip4/127.0.0.1/tcp/0"}
+		client, err := NewClient(conf, privKey, "TestChain", tmlog.TestingLogger())
+		require.NoError(err)
+		client.SetGenesisHash(genesisHash)
+		require.NoError(client.Start(context.Background()))
+		t.Cleanup(func() { _ = client.Close() })
+		return client
+	}
+	connect := func(from, to *Client) error {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		return from.host.Connect(ctx, peer.AddrInfo{ID: to.host.ID(), Addrs: to.Addrs()})
+	}
+
+	client1 := startClient([32]byte{1})
+	client2 := startClient([32]byte{2})
+
+	disconnected := make(chan struct{}, 1)
+	client2.host.Network().Notify(&network.NotifyBundle{
+		DisconnectedF: func(_ network.Network, conn network.Conn) {
+			if conn.RemotePeer() == client1.host.ID() {
+				select {
+				case disconnected <- struct{}{}:
+				default:
+				}
+			}
+		},
+	})
+
+	// transport connection succeeds, then handshake disconnects the peer
+	require.NoError(connect(client2, client1))
+	select {
+	case <-disconnected:
+	case <-time.After(5 * time.Second):
+		t.Fatal("peer with different genesis wasn't disconnected")
+	}
+
+	// further connections are refused, in both directions
+	assert.Error(connect(client2, client1))
+	assert.Error(connect(client1, client2))
+	// responder reads handshake first, so it always detects the mismatch
+	assert.Equal(uint64(1), client1.RejectedPeers())
+	assert.Equal(network.NotConnected, client2.host.Network().Connectedness(client1.host.ID()))
+}
@@ -0,0 +1,89 @@
+package p2p
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/network"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/libp2p/go-libp2p/p2p/protocol/ping"
+)
+
+// keepAlive pings all connected peers every PingInterval, until ctx is done.
+//
+// Regular traffic prevents idle connections from being silently dropped by NATs and firewalls. Peers that don't
+// respond within PingTimeout are disconnected. After peers are disconnected, or if there are no peers at all, seed
+// nodes are re-dialed and peer discovery is repeated to find healthy peers.
+func (c *Client) keepAlive(ctx context.Context) {
+	ticker := time.NewTicker(c.conf.PingInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			disconnected := c.pingPeers(ctx)
+			if ctx.Err() == nil && (disconnected > 0 || len(c.host.Network().Peers()) == 0) {
+				c.reconnect(ctx)
+			}
+		}
+	}
+}
+
+// pingPeers pings all connected peers and returns number of peers disconnected because they didn't respond.
+func (c *Client) pingPeers(ctx context.Context) int {
+	var wg sync.WaitGroup
+	var disconnected int32
+	for _, p := range c.host.Network().Peers() {
+		wg.Add(1)
+		go func(p peer.ID) {
+			defer wg.Done()
+			if !c.pingPeer(ctx, p) {
+				atomic.AddInt32(&disconnected, 1)
+			}
+		}(p)
+	}
+	wg.Wait()
+	return int(disconnected)
+}
+
+// reconnect dials seed nodes the client is not connected to and looks for new peers.
+func (c *Client) reconnect(ctx context.Context) {
+	for _, seed := range c.seeds {
+		if seed.ID == c.host.ID() || c.host.Network().Connectedness(seed.ID) == network.Connected {
+			continue
+		}
+		go c.tryConnect(ctx, seed)
+	}
+	if err := c.findPeers(ctx); err != nil {
+		c.logger.Debug("failed to find peers", "error", err)
+	}
+}
+
+// pingPeer pings the peer and disconnects it if it doesn't respond. It returns false if peer was disconnected.
+func (c *Client) pingPeer(ctx context.Context, p peer.ID) bool {
+	pingCtx, cancel := context.WithTimeout(ctx, c.conf.PingTimeout)
+	defer cancel()
+
+	res, ok := <-ping.Ping(pingCtx, c.host, p)
+	if !ok {
+		// result channel is closed without result when context is done
+		res.Error = pingCtx.Err()
+	}
+	if res.Error == nil {
+		c.logger.Debug("ping", "peer", p, "rtt", res.RTT)
+		return true
+	}
+	if ctx.Err() != nil {
+		return true
+	}
+
+	failures := atomic.AddUint64(&c.pingFailures, 1)
+	c.logger.Info("peer not responding to ping, disconnecting", "peer", p, "error", res.Error, "failures", failures)
+	if err := c.host.Network().ClosePeer(p); err != nil {
+		c.logger.Error("failed to disconnect peer", "peer", p, "error", err)
+	}
+	return false
+}
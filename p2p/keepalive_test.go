@@ -0,0 +1,100 @@
+package p2p
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	tmlog "github.com/lazyledger/lazyledger-core/libs/log"
+	"github.com/libp2p/go-libp2p-core/network"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/libp2p/go-libp2p/p2p/protocol/ping"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeepAlive(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	// TestLogger can't be used, as pings are logged in background after test completes
+	logger := tmlog.TestingLogger()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	clients := startTestNetwork(ctx, t, 2, map[int]hostDescr{
+		0: hostDescr{conns: []int{}, chainID: "1", pingInterval: 100 * time.Millisecond, pingTimeout: 200 * time.Millisecond},
+		1: hostDescr{conns: []int{0}, chainID: "1"},
+	}, logger)
+	clients.WaitForDHT()
+
+	id1 := clients[1].host.ID()
+	require.Equal(network.Connected, clients[0].host.Network().Connectedness(id1))
+
+	// responsive peer is not disconnected
+	time.Sleep(500 * time.Millisecond)
+	assert.Zero(clients[0].PingFailures())
+	assert.Equal(network.Connected, clients[0].host.Network().Connectedness(id1))
+
+	// peer stops responding to pings
+	disconnected := make(chan peer.ID, 1)
+	clients[0].host.Network().Notify(&network.NotifyBundle{
+		DisconnectedF: func(_ network.Network, conn network.Conn) {
+			select {
+			case disconnected <- conn.RemotePeer():
+			default:
+			}
+		},
+	})
+	clients[1].host.SetStreamHandler(ping.ID, func(s network.Stream) {
+		<-ctx.Done()
+		_ = s.Reset()
+	})
+
+	assert.Eventually(func() bool {
+		return clients[0].PingFailures() > 0
+	}, 2*time.Second, 50*time.Millisecond)
+	select {
+	case p := <-disconnected:
+		assert.Equal(id1, p)
+	case <-time.After(time.Second):
+		t.Error("peer not disconnected")
+	}
+}
+
+func TestKeepAliveReconnect(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	logger := tmlog.TestingLogger()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	clients := startTestNetwork(ctx, t, 2, map[int]hostDescr{
+		0: hostDescr{conns: []int{}, chainID: "1"},
+		1: hostDescr{conns: []int{0}, chainID: "1", pingInterval: 100 * time.Millisecond, pingTimeout: 200 * time.Millisecond},
+	}, logger)
+	clients.WaitForDHT()
+
+	id0 := clients[0].host.ID()
+	require.Equal(network.Connected, clients[1].host.Network().Connectedness(id0))
+
+	// seed stops responding to pings
+	clients[0].host.SetStreamHandler(ping.ID, func(s network.Stream) {
+		<-ctx.Done()
+		_ = s.Reset()
+	})
+	assert.Eventually(func() bool {
+		return clients[1].PingFailures() > 0
+	}, 2*time.Second, 50*time.Millisecond)
+
+	// seed recovers and is re-dialed
+	ping.NewPingService(clients[0].host)
+	failures := clients[1].PingFailures()
+	assert.Eventually(func() bool {
+		return clients[1].host.Network().Connectedness(id0) == network.Connected
+	}, 2*time.Second, 50*time.Millisecond)
+	time.Sleep(500 * time.Millisecond)
+	assert.LessOrEqual(clients[1].PingFailures(), failures+1)
+	assert.Equal(network.Connected, clients[1].host.Network().Connectedness(id0))
+}
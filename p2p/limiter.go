@@ -0,0 +1,96 @@
+package p2p
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/libp2p/go-libp2p-core/host"
+	"github.com/libp2p/go-libp2p-core/network"
+	"github.com/libp2p/go-libp2p-core/protocol"
+	dht "github.com/libp2p/go-libp2p-kad-dht"
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+
+	"github.com/lazyledger/optimint/log"
+)
+
+// unlimitedProtocols are protocols exempt from inbound stream limit. Gossipsub and DHT keep long-lived streams
+// open to every connected peer, so limiting them would cap the number of peers instead of the load.
+var unlimitedProtocols = map[protocol.ID]bool{
+	pubsub.GossipSubID_v10:           true,
+	pubsub.GossipSubID_v11:           true,
+	pubsub.FloodSubID:                true,
+	dht.DefaultPrefix + "/kad/1.0.0": true,
+}
+
+// limitedHost wraps host.Host and caps the number of concurrently handled inbound streams per protocol.
+//
+// Protocols register their stream handlers on the host, so limit is applied to all of them, except for
+// unlimitedProtocols. Streams exceeding the limit are reset.
+type limitedHost struct {
+	host.Host
+
+	limit  int
+	logger log.Logger
+
+	mtx    sync.Mutex
+	active map[protocol.ID]int
+
+	// rejected is a number of reset streams; accessed atomically
+	rejected uint64
+}
+
+var _ host.Host = &limitedHost{}
+
+func newLimitedHost(h host.Host, limit int, logger log.Logger) *limitedHost {
+	return &limitedHost{
+		Host:   h,
+		limit:  limit,
+		logger: logger,
+		active: make(map[protocol.ID]int),
+	}
+}
+
+func (h *limitedHost) SetStreamHandler(pid protocol.ID, handler network.StreamHandler) {
+	h.Host.SetStreamHandler(pid, h.limitHandler(pid, handler))
+}
+
+func (h *limitedHost) SetStreamHandlerMatch(pid protocol.ID, match func(string) bool, handler network.StreamHandler) {
+	h.Host.SetStreamHandlerMatch(pid, match, h.limitHandler(pid, handler))
+}
+
+func (h *limitedHost) limitHandler(pid protocol.ID, handler network.StreamHandler) network.StreamHandler {
+	if unlimitedProtocols[pid] {
+		return handler
+	}
+	return func(s network.Stream) {
+		if !h.acquire(pid) {
+			rejected := atomic.AddUint64(&h.rejected, 1)
+			h.logger.Debug("too many inbound streams, resetting", "protocol", pid, "peer", s.Conn().RemotePeer(), "rejected", rejected)
+			_ = s.Reset()
+			return
+		}
+		defer h.release(pid)
+		handler(s)
+	}
+}
+
+func (h *limitedHost) acquire(pid protocol.ID) bool {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+	if h.active[pid] >= h.limit {
+		return false
+	}
+	h.active[pid]++
+	return true
+}
+
+func (h *limitedHost) release(pid protocol.ID) {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+	h.active[pid]--
+}
+
+// Rejected returns number of inbound streams reset because of the limit.
+func (h *limitedHost) Rejected() uint64 {
+	return atomic.LoadUint64(&h.rejected)
+}
@@ -0,0 +1,124 @@
+package p2p
+
+import (
+	"context"
+	"crypto/rand"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/crypto"
+	"github.com/libp2p/go-libp2p-core/network"
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	mocknet "github.com/libp2p/go-libp2p/p2p/net/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lazyledger/optimint/config"
+)
+
+func TestLimitedHost(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	const proto = "/test/1.0.0"
+	const limit = 2
+
+	mnet, err := mocknet.FullMeshLinked(context.Background(), 2)
+	require.NoError(err)
+	hosts := mnet.Hosts()
+
+	server := newLimitedHost(hosts[0], limit, &TestLogger{t})
+	handled := make(chan struct{}, limit+1)
+	release := make(chan struct{})
+	server.SetStreamHandler(proto, func(s network.Stream) {
+		handled <- struct{}{}
+		<-release
+		_ = s.Close()
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	for i := 0; i < limit+1; i++ {
+		s, err := hosts[1].NewStream(ctx, hosts[0].ID(), proto)
+		require.NoError(err)
+		// stream handler is called on first write
+		_, err = s.Write([]byte{1})
+		require.NoError(err)
+	}
+
+	for i := 0; i < limit; i++ {
+		select {
+		case <-handled:
+		case <-ctx.Done():
+			t.Fatal("timeout waiting for stream handlers")
+		}
+	}
+
+	// excess stream is reset (handlers are called concurrently, so it's not known which one)
+	require.Eventually(func() bool {
+		return server.Rejected() == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Len(handled, 0)
+
+	// after streams are released, new streams are accepted
+	close(release)
+	require.Eventually(func() bool {
+		server.mtx.Lock()
+		defer server.mtx.Unlock()
+		return server.active[proto] == 0
+	}, 5*time.Second, 10*time.Millisecond)
+
+	s, err := hosts[1].NewStream(ctx, hosts[0].ID(), proto)
+	require.NoError(err)
+	_, err = s.Write([]byte{1})
+	require.NoError(err)
+	select {
+	case <-handled:
+	case <-ctx.Done():
+		t.Fatal("timeout waiting for stream handler")
+	}
+	assert.Equal(uint64(1), server.Rejected())
+}
+
+func TestLimitedHostUnlimitedProtocols(t *testing.T) {
+	require := require.New(t)
+
+	mnet, err := mocknet.FullMeshLinked(context.Background(), 2)
+	require.NoError(err)
+	hosts := mnet.Hosts()
+
+	server := newLimitedHost(hosts[0], 1, &TestLogger{t})
+	handled := make(chan struct{}, 2)
+	release := make(chan struct{})
+	defer close(release)
+	server.SetStreamHandler(pubsub.GossipSubID_v11, func(s network.Stream) {
+		handled <- struct{}{}
+		<-release
+		_ = s.Close()
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// long-lived streams of gossipsub are not limited
+	for i := 0; i < 2; i++ {
+		s, err := hosts[1].NewStream(ctx, hosts[0].ID(), pubsub.GossipSubID_v11)
+		require.NoError(err)
+		_, err = s.Write([]byte{1})
+		require.NoError(err)
+		select {
+		case <-handled:
+		case <-ctx.Done():
+			t.Fatal("timeout waiting for stream handler")
+		}
+	}
+	require.Zero(server.Rejected())
+}
+
+func TestRejectedStreamsNotStarted(t *testing.T) {
+	privKey, _, _ := crypto.GenerateEd25519Key(rand.Reader)
+	client, err := NewClient(config.P2PConfig{}, privKey, "test", &TestLogger{t})
+	require.NoError(t, err)
+	require.Zero(t, client.RejectedStreams())
+}
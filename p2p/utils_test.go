@@ -7,6 +7,7 @@ import (
 	"net"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/libp2p/go-libp2p-core/crypto"
 	"github.com/libp2p/go-libp2p-core/peer"
@@ -38,6 +39,12 @@ type hostDescr struct {
 	chainID string
 	conns   []int
 	realKey bool
+	txLanes []string
+	// isolated host is linked only with hosts from conns and hosts that have it in their conns
+	isolated bool
+
+	pingInterval time.Duration
+	pingTimeout  time.Duration
 }
 
 // copied from libp2p net/mock
@@ -85,8 +92,23 @@ func startTestNetwork(ctx context.Context, t *testing.T, n int, conf map[int]hos
 		}
 	}
 
-	err := mnet.LinkAll()
-	require.NoError(err)
+	linked := func(i, j int) bool {
+		for _, c := range conf[i].conns {
+			if c == j {
+				return true
+			}
+		}
+		return false
+	}
+	for i := 0; i < n; i++ {
+		for j := i + 1; j < n; j++ {
+			if (conf[i].isolated || conf[j].isolated) && !linked(i, j) && !linked(j, i) {
+				continue
+			}
+			_, err := mnet.LinkPeers(mnet.Peers()[i], mnet.Peers()[j])
+			require.NoError(err)
+		}
+	}
 
 	// prepare seed node lists
 	seeds := make([]string, n)
@@ -102,7 +124,10 @@ func startTestNetwork(ctx context.Context, t *testing.T, n int, conf map[int]hos
 	clients := make([]*Client, n)
 	for i := 0; i < n; i++ {
 		client, err := NewClient(config.P2PConfig{
-			Seeds: seeds[i]},
+			Seeds:        seeds[i],
+			TxLanes:      conf[i].txLanes,
+			PingInterval: conf[i].pingInterval,
+			PingTimeout:  conf[i].pingTimeout},
 			mnet.Hosts()[i].Peerstore().PrivKey(mnet.Hosts()[i].ID()),
 			conf[i].chainID,
 			logger)
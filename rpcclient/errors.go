@@ -0,0 +1,14 @@
+package rpcclient
+
+import "errors"
+
+var (
+	// ErrGenesisTooLarge is returned by Genesis when genesis document has to be fetched with GenesisChunked.
+	ErrGenesisTooLarge = errors.New("genesis document too large, use GenesisChunked")
+	// ErrInvalidChunkID is returned by GenesisChunked when requested chunk doesn't exist.
+	ErrInvalidChunkID = errors.New("invalid genesis chunk ID")
+	// ErrUnhealthy is returned by Health when some of node loops are stalled.
+	ErrUnhealthy = errors.New("node is unhealthy")
+	// ErrTxEvicted is returned by BroadcastTxCommit when transaction is dropped from mempool before inclusion in block.
+	ErrTxEvicted = errors.New("tx evicted from mempool")
+)
@@ -1,14 +1,19 @@
 package rpcclient
 
 import (
+	"bytes"
 	"context"
+	"encoding/base64"
 	"errors"
 	"fmt"
+	"strings"
+	"sync"
 	"time"
 
 	abci "github.com/lazyledger/lazyledger-core/abci/types"
 	"github.com/lazyledger/lazyledger-core/config"
 	tmbytes "github.com/lazyledger/lazyledger-core/libs/bytes"
+	tmjson "github.com/lazyledger/lazyledger-core/libs/json"
 	tmpubsub "github.com/lazyledger/lazyledger-core/libs/pubsub"
 	tmquery "github.com/lazyledger/lazyledger-core/libs/pubsub/query"
 	"github.com/lazyledger/lazyledger-core/proxy"
@@ -19,13 +24,28 @@ import (
 
 	"github.com/lazyledger/optimint/mempool"
 	"github.com/lazyledger/optimint/node"
+	optimint "github.com/lazyledger/optimint/types"
 )
 
 const (
 	// TODO(tzdybal): make this configurable
 	SubscribeTimeout = 5 * time.Second
+
+	// txEvictedCapacity is the capacity of EventTxEvicted subscription used in BroadcastTxCommit.
+	// Subscription receives evictions of all transactions, not only the broadcasted one.
+	txEvictedCapacity = 100
+
+	// genesisChunkSize is the maximum size of genesis chunk returned by GenesisChunked (same as in Tendermint).
+	genesisChunkSize = 16 * 1024 * 1024
 )
 
+// ResultGenesisChunk is the output format of GenesisChunked (same as in Tendermint).
+type ResultGenesisChunk struct {
+	ChunkNumber int    `json:"chunk"`
+	TotalChunks int    `json:"total"`
+	Data        string `json:"data"`
+}
+
 var _ rpcclient.Client = &Local{}
 
 type Local struct {
@@ -34,6 +54,10 @@ type Local struct {
 	config *config.RPCConfig
 
 	node *node.Node
+
+	genesisOnce     sync.Once
+	genesisChunks   []string
+	genesisChunkErr error
 }
 
 func NewLocal(node *node.Node) *Local {
@@ -72,6 +96,7 @@ func (l *Local) ABCIQueryWithOptions(ctx context.Context, path string, data tmby
 }
 
 // BroadcastTxCommit returns with the responses from CheckTx and DeliverTx.
+// If node publishes tx eviction events, ErrTxEvicted is returned as soon as tx is dropped from mempool.
 // More: https://docs.tendermint.com/master/rpc/#/Tx/broadcast_tx_commit
 func (l *Local) BroadcastTxCommit(ctx context.Context, tx types.Tx) (*ctypes.ResultBroadcastTxCommit, error) {
 	// This implementation corresponds to Tendermints implementation from rpc/core/mempool.go.
@@ -101,6 +126,25 @@ func (l *Local) BroadcastTxCommit(ctx context.Context, tx types.Tx) (*ctypes.Res
 		}
 	}()
 
+	// Subscribe to tx being evicted from mempool, if node publishes such events.
+	var evictedCh <-chan tmpubsub.Message
+	if l.node.TxEvictionEvents() {
+		// subscriber is unique per tx, because single subscriber can't subscribe to the same query multiple times
+		evictionSubscriber := fmt.Sprintf("broadcast_tx_commit/%X", tx.Hash())
+		evictedSub, err := l.EventBus.Subscribe(subCtx, evictionSubscriber, node.EventQueryTxEvicted, txEvictedCapacity)
+		if err != nil {
+			err = fmt.Errorf("failed to subscribe to tx eviction: %w", err)
+			l.Logger.Error("Error on broadcast_tx_commit", "err", err)
+			return nil, err
+		}
+		defer func() {
+			if err := l.EventBus.Unsubscribe(context.Background(), evictionSubscriber, node.EventQueryTxEvicted); err != nil {
+				l.Logger.Error("Error unsubscribing from eventBus", "err", err)
+			}
+		}()
+		evictedCh = evictedSub.Out()
+	}
+
 	// Broadcast tx and wait for CheckTx result
 	checkTxResCh := make(chan *abci.Response, 1)
 	err = l.node.Mempool.CheckTx(tx, func(res *abci.Response) {
@@ -108,7 +152,7 @@ func (l *Local) BroadcastTxCommit(ctx context.Context, tx types.Tx) (*ctypes.Res
 	}, mempool.TxInfo{Context: ctx})
 	if err != nil {
 		l.Logger.Error("Error on broadcastTxCommit", "err", err)
-		return nil, fmt.Errorf("error on broadcastTxCommit: %v", err)
+		return nil, fmt.Errorf("error on broadcastTxCommit: %w", err)
 	}
 	checkTxResMsg := <-checkTxResCh
 	checkTxRes := checkTxResMsg.GetCheckTx()
@@ -116,42 +160,57 @@ func (l *Local) BroadcastTxCommit(ctx context.Context, tx types.Tx) (*ctypes.Res
 		return &ctypes.ResultBroadcastTxCommit{
 			CheckTx:   *checkTxRes,
 			DeliverTx: abci.ResponseDeliverTx{},
-			Hash:      tx.Hash(),
+			Hash:      txHash(tx),
 		}, nil
 	}
 
-	// Wait for the tx to be included in a block or timeout.
-	select {
-	case msg := <-deliverTxSub.Out(): // The tx was included in a block.
-		deliverTxRes := msg.Data().(types.EventDataTx)
-		return &ctypes.ResultBroadcastTxCommit{
-			CheckTx:   *checkTxRes,
-			DeliverTx: deliverTxRes.Result,
-			Hash:      tx.Hash(),
-			Height:    deliverTxRes.Height,
-		}, nil
-	case <-deliverTxSub.Cancelled():
-		var reason string
-		if deliverTxSub.Err() == nil {
-			reason = "Tendermint exited"
-		} else {
-			reason = deliverTxSub.Err().Error()
+	// Wait for the tx to be included in a block, evicted from mempool or timeout.
+	timeout := time.After(l.config.TimeoutBroadcastTxCommit)
+	for {
+		select {
+		case msg := <-deliverTxSub.Out(): // The tx was included in a block.
+			deliverTxRes := msg.Data().(types.EventDataTx)
+			return &ctypes.ResultBroadcastTxCommit{
+				CheckTx:   *checkTxRes,
+				DeliverTx: deliverTxRes.Result,
+				Hash:      txHash(tx),
+				Height:    deliverTxRes.Height,
+			}, nil
+		case <-deliverTxSub.Cancelled():
+			var reason string
+			if deliverTxSub.Err() == nil {
+				reason = "Tendermint exited"
+			} else {
+				reason = deliverTxSub.Err().Error()
+			}
+			err = fmt.Errorf("deliverTxSub was cancelled (reason: %s)", reason)
+			l.Logger.Error("Error on broadcastTxCommit", "err", err)
+			return &ctypes.ResultBroadcastTxCommit{
+				CheckTx:   *checkTxRes,
+				DeliverTx: abci.ResponseDeliverTx{},
+				Hash:      txHash(tx),
+			}, err
+		case msg := <-evictedCh:
+			evicted := msg.Data().(node.EventDataTxEvicted)
+			if !bytes.Equal(evicted.Hash, tx.Hash()) {
+				continue
+			}
+			err = fmt.Errorf("%w: %s", ErrTxEvicted, evicted.Reason)
+			l.Logger.Info("Tx evicted on broadcastTxCommit", "err", err)
+			return &ctypes.ResultBroadcastTxCommit{
+				CheckTx:   *checkTxRes,
+				DeliverTx: abci.ResponseDeliverTx{},
+				Hash:      txHash(tx),
+			}, err
+		case <-timeout:
+			err = errors.New("timed out waiting for tx to be included in a block")
+			l.Logger.Error("Error on broadcastTxCommit", "err", err)
+			return &ctypes.ResultBroadcastTxCommit{
+				CheckTx:   *checkTxRes,
+				DeliverTx: abci.ResponseDeliverTx{},
+				Hash:      txHash(tx),
+			}, err
 		}
-		err = fmt.Errorf("deliverTxSub was cancelled (reason: %s)", reason)
-		l.Logger.Error("Error on broadcastTxCommit", "err", err)
-		return &ctypes.ResultBroadcastTxCommit{
-			CheckTx:   *checkTxRes,
-			DeliverTx: abci.ResponseDeliverTx{},
-			Hash:      tx.Hash(),
-		}, err
-	case <-time.After(l.config.TimeoutBroadcastTxCommit):
-		err = errors.New("timed out waiting for tx to be included in a block")
-		l.Logger.Error("Error on broadcastTxCommit", "err", err)
-		return &ctypes.ResultBroadcastTxCommit{
-			CheckTx:   *checkTxRes,
-			DeliverTx: abci.ResponseDeliverTx{},
-			Hash:      tx.Hash(),
-		}, err
 	}
 }
 
@@ -164,7 +223,7 @@ func (l *Local) BroadcastTxAsync(ctx context.Context, tx types.Tx) (*ctypes.Resu
 	if err != nil {
 		return nil, err
 	}
-	return &ctypes.ResultBroadcastTx{Hash: tx.Hash()}, nil
+	return &ctypes.ResultBroadcastTx{Hash: txHash(tx)}, nil
 }
 
 // BroadcastTxSync returns with the response from CheckTx. Does not wait for
@@ -185,7 +244,7 @@ func (l *Local) BroadcastTxSync(ctx context.Context, tx types.Tx) (*ctypes.Resul
 		Data:      r.Data,
 		Log:       r.Log,
 		Codespace: r.Codespace,
-		Hash:      tx.Hash(),
+		Hash:      txHash(tx),
 	}, nil
 }
 
@@ -225,8 +284,33 @@ func (l *Local) Unsubscribe(ctx context.Context, subscriber, query string) error
 }
 
 func (l *Local) Genesis(ctx context.Context) (*ctypes.ResultGenesis, error) {
-	// needs genesis provider
-	panic("Genesis - not implemented!")
+	genesis, err := l.node.Genesis()
+	if err != nil {
+		return nil, err
+	}
+	// clients should use GenesisChunked for large genesis documents
+	if len(genesis.AppState) > genesisChunkSize {
+		return nil, ErrGenesisTooLarge
+	}
+	return &ctypes.ResultGenesis{Genesis: genesis}, nil
+}
+
+// GenesisChunked returns chunk of JSON-encoded genesis document, encoded with base64.
+//
+// Chunks have to be concatenated (after base64 decoding) to reconstruct the genesis document.
+func (l *Local) GenesisChunked(ctx context.Context, id uint) (*ResultGenesisChunk, error) {
+	chunks, err := l.getGenesisChunks()
+	if err != nil {
+		return nil, err
+	}
+	if int(id) >= len(chunks) {
+		return nil, fmt.Errorf("%w: %d (chunks: %d)", ErrInvalidChunkID, id, len(chunks))
+	}
+	return &ResultGenesisChunk{
+		ChunkNumber: int(id),
+		TotalChunks: len(chunks),
+		Data:        chunks[id],
+	}, nil
 }
 
 func (l *Local) BlockchainInfo(ctx context.Context, minHeight, maxHeight int64) (*ctypes.ResultBlockchainInfo, error) {
@@ -255,6 +339,9 @@ func (l *Local) ConsensusParams(ctx context.Context, height *int64) (*ctypes.Res
 }
 
 func (l *Local) Health(ctx context.Context) (*ctypes.ResultHealth, error) {
+	if stalled := l.node.StalledLoops(); len(stalled) > 0 {
+		return nil, fmt.Errorf("%w: stalled loops: %s", ErrUnhealthy, strings.Join(stalled, ", "))
+	}
 	return &ctypes.ResultHealth{}, nil
 }
 
@@ -305,7 +392,8 @@ func (l *Local) Status(ctx context.Context) (*ctypes.ResultStatus, error) {
 	latestBlockTimeNano := latest.Header.Time
 
 	result := &ctypes.ResultStatus{
-		// TODO(tzdybal): NodeInfo, ValidatorInfo
+		NodeInfo: l.node.Info(),
+		// TODO(tzdybal): ValidatorInfo
 		SyncInfo: ctypes.SyncInfo{
 			LatestBlockHash:   latestBlockHash[:],
 			LatestAppHash:     latestAppHash[:],
@@ -408,3 +496,38 @@ func (l *Local) query() proxy.AppConnQuery {
 func (l *Local) snapshot() proxy.AppConnSnapshot {
 	return l.node.ProxyApp().Snapshot()
 }
+
+func txHash(tx types.Tx) tmbytes.HexBytes {
+	hash := optimint.Tx(tx).Hash()
+	return hash[:]
+}
+
+func (l *Local) getGenesisChunks() ([]string, error) {
+	l.genesisOnce.Do(func() {
+		genesis, err := l.node.Genesis()
+		if err != nil {
+			l.genesisChunkErr = err
+			return
+		}
+		data, err := tmjson.Marshal(genesis)
+		if err != nil {
+			l.genesisChunkErr = err
+			return
+		}
+		l.genesisChunks = chunkGenesis(data, genesisChunkSize)
+	})
+	return l.genesisChunks, l.genesisChunkErr
+}
+
+// chunkGenesis splits data into base64 encoded chunks of at most size bytes (before encoding).
+func chunkGenesis(data []byte, size int) []string {
+	chunks := make([]string, 0, (len(data)+size-1)/size)
+	for i := 0; i < len(data); i += size {
+		end := i + size
+		if end > len(data) {
+			end = len(data)
+		}
+		chunks = append(chunks, base64.StdEncoding.EncodeToString(data[i:end]))
+	}
+	return chunks
+}
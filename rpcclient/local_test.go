@@ -3,6 +3,8 @@ package rpcclient
 import (
 	"context"
 	"crypto/rand"
+	"encoding/base64"
+	"errors"
 	"testing"
 	"time"
 
@@ -11,7 +13,9 @@ import (
 	"github.com/stretchr/testify/require"
 
 	abci "github.com/lazyledger/lazyledger-core/abci/types"
+	llcfg "github.com/lazyledger/lazyledger-core/config"
 	"github.com/lazyledger/lazyledger-core/libs/bytes"
+	tmjson "github.com/lazyledger/lazyledger-core/libs/json"
 	"github.com/lazyledger/lazyledger-core/libs/log"
 	"github.com/lazyledger/lazyledger-core/proxy"
 	"github.com/lazyledger/lazyledger-core/types"
@@ -19,8 +23,11 @@ import (
 	"github.com/libp2p/go-libp2p-core/peer"
 
 	"github.com/lazyledger/optimint/config"
+	"github.com/lazyledger/optimint/mempool"
 	"github.com/lazyledger/optimint/mocks"
 	"github.com/lazyledger/optimint/node"
+	"github.com/lazyledger/optimint/node/testutil"
+	optimint "github.com/lazyledger/optimint/types"
 )
 
 var expectedInfo = abci.ResponseInfo{
@@ -115,6 +122,34 @@ func TestBroadcastTxSync(t *testing.T) {
 	mockApp.AssertExpectations(t)
 }
 
+func TestBroadcastTxMempoolErrors(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	expectedTx := []byte("tx data")
+
+	mockApp, rpc := getRPC(t)
+	mockApp.On("CheckTx", abci.RequestCheckTx{Tx: expectedTx}).Once().Return(abci.ResponseCheckTx{})
+
+	// app rejections are returned in result, mempool errors are returned as errors
+	res, err := rpc.BroadcastTxSync(context.Background(), expectedTx)
+	require.NoError(err)
+	assert.Equal(abci.CodeTypeOK, res.Code)
+
+	res, err = rpc.BroadcastTxSync(context.Background(), expectedTx)
+	assert.ErrorIs(err, mempool.ErrTxInCache)
+	assert.Nil(res)
+
+	commitRes, err := rpc.BroadcastTxCommit(context.Background(), expectedTx)
+	assert.ErrorIs(err, mempool.ErrTxInCache)
+	assert.Nil(commitRes)
+
+	_, err = rpc.BroadcastTxAsync(context.Background(), make([]byte, llcfg.DefaultMempoolConfig().MaxTxBytes+1))
+	assert.True(errors.As(err, &mempool.ErrTxTooLarge{}))
+
+	mockApp.AssertExpectations(t)
+}
+
 func TestBroadcastTxCommit(t *testing.T) {
 	assert := assert.New(t)
 	require := require.New(t)
@@ -165,12 +200,128 @@ func TestBroadcastTxCommit(t *testing.T) {
 	mockApp.AssertExpectations(t)
 }
 
+func TestBroadcastTxCommitEvicted(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	app := &mocks.Application{}
+	app.On("CheckTx", mock.Anything).Return(abci.ResponseCheckTx{})
+	key, _, _ := crypto.GenerateEd25519Key(rand.Reader)
+	node, err := node.NewNode(context.Background(), config.NodeConfig{TxEvictionEvents: true}, key, proxy.NewLocalClientCreator(app), &types.GenesisDoc{}, log.TestingLogger())
+	require.NoError(err)
+	rpc := NewLocal(node)
+
+	expectedTx := types.Tx("tx data")
+	otherTx := types.Tx("other tx")
+	require.NoError(node.Mempool.CheckTx(otherTx, nil, mempool.TxInfo{}))
+	mp := node.Mempool.(*mempool.CListMempool)
+
+	go func() {
+		time.Sleep(mockTxProcessingTime)
+		// eviction of other transaction is ignored
+		mp.RemoveTxByKey(mempool.TxKey(otherTx), true)
+		time.Sleep(mockTxProcessingTime)
+		mp.RemoveTxByKey(mempool.TxKey(expectedTx), true)
+	}()
+
+	start := time.Now()
+	res, err := rpc.BroadcastTxCommit(context.Background(), expectedTx)
+	assert.ErrorIs(err, ErrTxEvicted)
+	assert.Contains(err.Error(), mempool.ErrTxRemoved.Error())
+	require.NotNil(res)
+	assert.EqualValues(expectedTx.Hash(), res.Hash)
+	assert.Less(int64(time.Since(start)), int64(rpc.config.TimeoutBroadcastTxCommit))
+	assert.Equal(0, node.Mempool.Size())
+}
+
+func TestStatus(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	_, rpc := getRPC(t)
+	err := rpc.node.BlockStore.SaveBlock(&optimint.Block{Header: optimint.Header{Height: 1, Time: 12345}})
+	require.NoError(err)
+
+	status, err := rpc.Status(context.Background())
+	require.NoError(err)
+	require.NotNil(status)
+	assert.Equal("test-node", status.NodeInfo.Moniker)
+	assert.EqualValues(1, status.SyncInfo.LatestBlockHeight)
+}
+
+func TestGenesis(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	genesis := &types.GenesisDoc{
+		ChainID:  "genesis-test",
+		AppState: []byte(`{"accounts":["alice","bob"]}`),
+	}
+	rpc := NewLocal(testutil.NewTestNode(t, testutil.WithGenesis(genesis)))
+
+	res, err := rpc.Genesis(context.Background())
+	require.NoError(err)
+	require.NotNil(res)
+	assert.Equal(genesis.ChainID, res.Genesis.ChainID)
+	assert.Equal(genesis.AppState, res.Genesis.AppState)
+
+	chunk, err := rpc.GenesisChunked(context.Background(), 0)
+	require.NoError(err)
+	require.NotNil(chunk)
+	assert.Equal(0, chunk.ChunkNumber)
+	assert.Equal(1, chunk.TotalChunks)
+	data, err := base64.StdEncoding.DecodeString(chunk.Data)
+	require.NoError(err)
+	var decoded types.GenesisDoc
+	require.NoError(tmjson.Unmarshal(data, &decoded))
+	assert.Equal(genesis.ChainID, decoded.ChainID)
+
+	chunk, err = rpc.GenesisChunked(context.Background(), 1)
+	assert.True(errors.Is(err, ErrInvalidChunkID))
+	assert.Nil(chunk)
+}
+
+func TestChunkGenesis(t *testing.T) {
+	data := []byte("the quick brown fox jumps over the lazy dog!")
+
+	cases := []struct {
+		name           string
+		size           int
+		expectedChunks int
+	}{
+		{"single chunk", len(data) + 1, 1},
+		{"exact size", len(data), 1},
+		{"exact multiple", 11, 4},
+		{"remainder", 7, 7},
+		{"byte chunks", 1, len(data)},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			chunks := chunkGenesis(data, c.size)
+			assert.Len(chunks, c.expectedChunks)
+
+			var reassembled []byte
+			for _, chunk := range chunks {
+				decoded, err := base64.StdEncoding.DecodeString(chunk)
+				require.NoError(err)
+				assert.LessOrEqual(len(decoded), c.size)
+				reassembled = append(reassembled, decoded...)
+			}
+			assert.Equal(data, reassembled)
+		})
+	}
+}
+
 func getRPC(t *testing.T) (*mocks.Application, *Local) {
 	t.Helper()
 	require := require.New(t)
 	app := &mocks.Application{}
 	key, _, _ := crypto.GenerateEd25519Key(rand.Reader)
-	node, err := node.NewNode(context.Background(), config.NodeConfig{}, key, proxy.NewLocalClientCreator(app), &types.GenesisDoc{}, log.TestingLogger())
+	node, err := node.NewNode(context.Background(), config.NodeConfig{Moniker: "test-node"}, key, proxy.NewLocalClientCreator(app), &types.GenesisDoc{}, log.TestingLogger())
 	require.NoError(err)
 	require.NotNil(node)
 
@@ -1,8 +1,14 @@
 package store
 
-import "github.com/dgraph-io/badger/v3"
+import (
+	"errors"
+
+	"github.com/dgraph-io/badger/v3"
+)
 
 var _ KVStore = &BadgerKV{}
+var _ Batch = &BadgerBatch{}
+var _ Iterator = &BadgerIterator{}
 
 type BadgerKV struct {
 	db *badger.DB
@@ -12,6 +18,9 @@ func (b *BadgerKV) Get(key []byte) ([]byte, error) {
 	txn := b.db.NewTransaction(false)
 	defer txn.Discard()
 	item, err := txn.Get(key)
+	if errors.Is(err, badger.ErrKeyNotFound) {
+		return nil, ErrKeyNotFound
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -37,3 +46,69 @@ func (b *BadgerKV) Delete(key []byte) error {
 	}
 	return txn.Commit()
 }
+
+// NewBatch creates a Batch for atomic updates.
+func (b *BadgerKV) NewBatch() Batch {
+	return &BadgerBatch{txn: b.db.NewTransaction(true)}
+}
+
+// BadgerBatch is a Batch backed by badger transaction.
+type BadgerBatch struct {
+	txn *badger.Txn
+}
+
+func (bb *BadgerBatch) Set(key, value []byte) error {
+	return bb.txn.Set(key, value)
+}
+
+func (bb *BadgerBatch) Delete(key []byte) error {
+	return bb.txn.Delete(key)
+}
+
+func (bb *BadgerBatch) Commit() error {
+	return bb.txn.Commit()
+}
+
+func (bb *BadgerBatch) Discard() {
+	bb.txn.Discard()
+}
+
+// PrefixIterator returns Iterator over all keys with given prefix, in lexicographical order.
+func (b *BadgerKV) PrefixIterator(prefix []byte) Iterator {
+	txn := b.db.NewTransaction(false)
+	it := txn.NewIterator(badger.IteratorOptions{Prefix: prefix})
+	it.Rewind()
+	return &BadgerIterator{txn: txn, it: it}
+}
+
+// BadgerIterator is an Iterator backed by badger read-only transaction.
+type BadgerIterator struct {
+	txn *badger.Txn
+	it  *badger.Iterator
+}
+
+func (bi *BadgerIterator) Valid() bool {
+	return bi.it.Valid()
+}
+
+func (bi *BadgerIterator) Next() {
+	bi.it.Next()
+}
+
+func (bi *BadgerIterator) Key() []byte {
+	return bi.it.Item().KeyCopy(nil)
+}
+
+func (bi *BadgerIterator) Value() ([]byte, error) {
+	return bi.it.Item().ValueCopy(nil)
+}
+
+func (bi *BadgerIterator) Discard() {
+	bi.it.Close()
+	bi.txn.Discard()
+}
+
+// Close closes the underlying database.
+func (b *BadgerKV) Close() error {
+	return b.db.Close()
+}
@@ -0,0 +1,101 @@
+package store
+
+import (
+	"encoding/binary"
+	"fmt"
+	"sort"
+
+	abci "github.com/lazyledger/lazyledger-core/abci/types"
+	"go.uber.org/multierr"
+)
+
+var blockEventPrefix = [1]byte{5}
+
+// BlockIndexer indexes blocks by attributes of block-level (BeginBlock and EndBlock) events.
+//
+// Only configured attributes are indexed. Attributes are identified by composite keys in the form
+// "<event type>.<attribute key>", the same as in Tendermint event queries.
+//
+// Every indexed attribute is stored under separate key (composite key, value and height), so indexing doesn't
+// require reading (and rewriting) all heights with the same attribute value.
+type BlockIndexer struct {
+	db   KVStore
+	keys map[string]bool
+}
+
+// NewBlockIndexer returns BlockIndexer backed by db, indexing attributes identified by given composite keys.
+func NewBlockIndexer(db KVStore, compositeKeys []string) *BlockIndexer {
+	keys := make(map[string]bool, len(compositeKeys))
+	for _, k := range compositeKeys {
+		keys[k] = true
+	}
+	return &BlockIndexer{db: db, keys: keys}
+}
+
+// Index adds block at given height to the index, using attributes of block-level events.
+func (bi *BlockIndexer) Index(height uint64, events []abci.Event) error {
+	batch := bi.db.NewBatch()
+	defer batch.Discard()
+
+	var err error
+	for _, event := range events {
+		for _, attr := range event.Attributes {
+			compositeKey := event.Type + "." + string(attr.Key)
+			if !bi.keys[compositeKey] {
+				continue
+			}
+			key := append(getBlockEventKey(compositeKey, string(attr.Value)), encodeHeight(height)...)
+			err = multierr.Append(err, batch.Set(key, []byte{}))
+		}
+	}
+	if err == nil {
+		err = batch.Commit()
+	}
+	if err != nil {
+		return fmt.Errorf("failed to index block events at height %d: %w", height, err)
+	}
+	return nil
+}
+
+// Search returns heights of all blocks with event attribute identified by compositeKey equal to value,
+// in ascending order.
+//
+// ErrNotIndexed is returned if compositeKey is not indexed.
+func (bi *BlockIndexer) Search(compositeKey, value string) ([]uint64, error) {
+	if !bi.keys[compositeKey] {
+		return nil, fmt.Errorf("%w: %s", ErrNotIndexed, compositeKey)
+	}
+	prefix := getBlockEventKey(compositeKey, value)
+	it := bi.db.PrefixIterator(prefix)
+	defer it.Discard()
+
+	var heights []uint64
+	for ; it.Valid(); it.Next() {
+		key := it.Key()
+		if len(key) != len(prefix)+8 {
+			continue
+		}
+		heights = append(heights, decodeHeight(key[len(prefix):]))
+	}
+	// heights are encoded in little-endian, so lexicographical order of keys is not numerical order
+	sort.Slice(heights, func(i, j int) bool { return heights[i] < heights[j] })
+	return heights, nil
+}
+
+// getBlockEventKey returns common prefix of index keys of given attribute value.
+//
+// Composite key and value are length-prefixed, so prefix of one value never matches keys of another.
+func getBlockEventKey(compositeKey, value string) []byte {
+	key := make([]byte, 0, len(blockEventPrefix)+2*binary.MaxVarintLen64+len(compositeKey)+len(value)+8)
+	key = append(key, blockEventPrefix[:]...)
+	key = appendUvarint(key, uint64(len(compositeKey)))
+	key = append(key, compositeKey...)
+	key = appendUvarint(key, uint64(len(value)))
+	return append(key, value...)
+}
+
+func appendUvarint(buf []byte, x uint64) []byte {
+	var tmp [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(tmp[:], x)
+	return append(buf, tmp[:n]...)
+}
@@ -0,0 +1,67 @@
+package store
+
+import (
+	"errors"
+	"testing"
+
+	abci "github.com/lazyledger/lazyledger-core/abci/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlockIndexer(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	kv := NewInMemoryKVStore()
+	bi := NewBlockIndexer(kv, []string{"rewards.validator", "upgrade.name"})
+
+	events := func(validator string) []abci.Event {
+		return []abci.Event{
+			{Type: "rewards", Attributes: []abci.EventAttribute{
+				{Key: []byte("validator"), Value: []byte(validator)},
+				{Key: []byte("amount"), Value: []byte("100")},
+			}},
+		}
+	}
+	// out of order and repeated indexing
+	require.NoError(bi.Index(3, events("alice")))
+	require.NoError(bi.Index(1, events("alice")))
+	require.NoError(bi.Index(2, events("bob")))
+	require.NoError(bi.Index(5, events("bo")))
+	require.NoError(bi.Index(256, events("alice")))
+	require.NoError(bi.Index(3, events("alice")))
+	require.NoError(bi.Index(4, append(events("alice"), abci.Event{
+		Type:       "upgrade",
+		Attributes: []abci.EventAttribute{{Key: []byte("name"), Value: []byte("v2")}},
+	})))
+
+	cases := []struct {
+		compositeKey string
+		value        string
+		expected     []uint64
+		expectedErr  error
+	}{
+		{"rewards.validator", "alice", []uint64{1, 3, 4, 256}, nil},
+		{"rewards.validator", "bob", []uint64{2}, nil},
+		{"rewards.validator", "bo", []uint64{5}, nil},
+		{"rewards.validator", "carol", nil, nil},
+		{"upgrade.name", "v2", []uint64{4}, nil},
+		{"rewards.amount", "100", nil, ErrNotIndexed},
+	}
+
+	// index is read back from the same store
+	reopened := NewBlockIndexer(kv, []string{"rewards.validator", "upgrade.name"})
+
+	for _, indexer := range []*BlockIndexer{bi, reopened} {
+		for _, c := range cases {
+			heights, err := indexer.Search(c.compositeKey, c.value)
+			if c.expectedErr != nil {
+				assert.True(errors.Is(err, c.expectedErr), err)
+			} else {
+				assert.NoError(err)
+			}
+			assert.Equal(c.expected, heights, c.compositeKey+"="+c.value)
+		}
+	}
+}
@@ -3,32 +3,147 @@ package store
 import (
 	"bytes"
 	"encoding/binary"
-	"encoding/gob"
+	"errors"
+	"fmt"
 	"sync"
 
-	"github.com/lazyledger/optimint/types"
-	"github.com/minio/sha256-simd"
 	"go.uber.org/multierr"
+
+	"github.com/lazyledger/optimint/log"
+	"github.com/lazyledger/optimint/types"
 )
 
 var (
 	blockPrefix = [1]byte{1}
 	indexPrefix = [1]byte{2}
+	heightKey   = [1]byte{3}
+	// commitPrefix = [1]byte{6}, as 4 and 5 are used by migrations and block indexer
+	commitPrefix = [1]byte{6}
+	baseKey      = [1]byte{7}
+	evictedKey   = [1]byte{8}
+	// 9 is used by da.DeduplicatingClient
 )
 
+// maxBatchDeletes limits the number of blocks deleted in a single batch, as size of badger transaction is limited.
+const maxBatchDeletes = 1000
+
+// EvictableFunc decides if block can be evicted from store with limited capacity (see WithCapacity).
+type EvictableFunc func(block *types.Block) bool
+
 type DefaultBlockStore struct {
-	db KVStore
+	db    KVStore
+	codec types.Codec
+
+	capacity  uint64
+	evictable EvictableFunc
 
 	height uint64
+	// base is the lowest stored height, or 0 if store is empty
+	base uint64
+	// evicted is the highest evicted height, or 0 if no blocks were evicted
+	evicted uint64
 
-	// mtx protects height
+	// mtx protects height, base and evicted
 	mtx sync.RWMutex
 }
 
 var _ BlockStore = &DefaultBlockStore{}
 
-func NewBlockStore() BlockStore {
-	return &DefaultBlockStore{db: NewInMemoryKVStore()}
+// BlockStoreOption sets an optional parameter on the DefaultBlockStore.
+type BlockStoreOption func(*DefaultBlockStore)
+
+// WithCodec sets the codec used to encode stored blocks. types.DefaultCodec is used by default.
+//
+// Codec is not recorded in the store, so the same codec has to be used every time store is opened.
+func WithCodec(codec types.Codec) BlockStoreOption {
+	return func(bs *DefaultBlockStore) { bs.codec = codec }
+}
+
+// WithCapacity limits the number of stored blocks. When it's exceeded, the oldest blocks are evicted, like in a ring
+// buffer. Block is evicted only if evictable returns true for it (for example, if it was submitted to DA layer);
+// otherwise eviction stops and store temporarily exceeds capacity. If evictable is nil, all blocks can be evicted.
+//
+// Loading block at evicted height returns ErrBlockEvicted. Capacity is intended for in-memory stores of demo and test
+// nodes, to keep memory bounded. Zero capacity means no limit.
+func WithCapacity(capacity uint64, evictable EvictableFunc) BlockStoreOption {
+	return func(bs *DefaultBlockStore) {
+		bs.capacity = capacity
+		bs.evictable = evictable
+	}
+}
+
+func NewBlockStore(options ...BlockStoreOption) BlockStore {
+	return newBlockStore(NewInMemoryKVStore(), options)
+}
+
+func newBlockStore(db KVStore, options []BlockStoreOption) *DefaultBlockStore {
+	bs := &DefaultBlockStore{db: db, codec: types.DefaultCodec}
+	for _, option := range options {
+		option(bs)
+	}
+	return bs
+}
+
+// OpenBlockStore returns block store backed by (possibly non-empty) KVStore.
+//
+// Stored blocks have to be contiguous, starting from the lowest stored height (genesis initial height, or the lowest
+// height that was not evicted). Base and height are updated in the same batches as blocks, so opening checks only
+// blocks at base and height, and that the latest block has commit if the previous one has (it wouldn't after crash
+// between SaveBlock and SaveCommit). ErrStoreGap is returned if check fails, as it means that store is corrupted.
+// Use Verify to check all stored blocks.
+//
+// Stores written in older format are migrated to StoreVersion. Stores written in newer format are rejected.
+func OpenBlockStore(db KVStore, logger log.Logger, options ...BlockStoreOption) (BlockStore, error) {
+	if err := migrate(db, logger); err != nil {
+		return nil, err
+	}
+	bs := newBlockStore(db, options)
+
+	height, err := bs.loadHeight()
+	if err != nil {
+		return nil, err
+	}
+	base, err := loadOptionalHeight(db, baseKey[:])
+	if err != nil {
+		return nil, err
+	}
+	evicted, err := loadOptionalHeight(db, evictedKey[:])
+	if err != nil {
+		return nil, err
+	}
+
+	if height > 0 {
+		if base == 0 || base > height {
+			return nil, fmt.Errorf("%w: base %d, height %d", ErrStoreGap, base, height)
+		}
+		for _, h := range []uint64{base, height} {
+			_, err := bs.db.Get(getIndexKey(h))
+			if errors.Is(err, ErrKeyNotFound) {
+				return nil, fmt.Errorf("%w: missing block at height %d (stored %d-%d)", ErrStoreGap, h, base, height)
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+		if height > base {
+			prevCommit, err := bs.hasCommit(height - 1)
+			if err != nil {
+				return nil, err
+			}
+			hasCommit, err := bs.hasCommit(height)
+			if err != nil {
+				return nil, err
+			}
+			if prevCommit && !hasCommit {
+				return nil, fmt.Errorf("%w: missing commit at height %d", ErrStoreGap, height)
+			}
+		}
+	}
+
+	bs.height = height
+	bs.base = base
+	bs.evicted = evicted
+	return bs, nil
 }
 
 func (bs *DefaultBlockStore) Height() uint64 {
@@ -37,60 +152,176 @@ func (bs *DefaultBlockStore) Height() uint64 {
 	return bs.height
 }
 
+func (bs *DefaultBlockStore) Base() uint64 {
+	bs.mtx.RLock()
+	defer bs.mtx.RUnlock()
+	return bs.base
+}
+
+// SaveBlock saves the block. Stored heights have to be contiguous, so block has to be at stored height, or directly
+// adjacent to stored heights; otherwise ErrNonContiguousHeight is returned. First saved block can be at any height.
 func (bs *DefaultBlockStore) SaveBlock(block *types.Block) error {
+	return bs.saveBlock(block, nil)
+}
+
+// SaveBlockWithCommit saves the block and its commit in a single batch, so crash can't leave block saved without
+// its commit. ErrCommitMismatch is returned if commit doesn't sign the block.
+func (bs *DefaultBlockStore) SaveBlockWithCommit(block *types.Block, commit *types.Commit) error {
+	return bs.saveBlock(block, commit)
+}
+
+// saveBlock saves the block and, if it's not nil, its commit.
+func (bs *DefaultBlockStore) saveBlock(block *types.Block, commit *types.Commit) error {
 	// TODO(tzdybal): proper serialization & hashing
-	hash, err := getHash(block)
-	if err != nil {
-		return err
-	}
+	hash := block.Header.Hash()
 	key := append(blockPrefix[:], hash[:]...)
 
-	height := make([]byte, 8)
-	binary.LittleEndian.PutUint64(height, block.Header.Height)
-	ikey := append(indexPrefix[:], height[:]...)
+	height := block.Header.Height
+	ikey := getIndexKey(height)
 
-	var value bytes.Buffer
-	enc := gob.NewEncoder(&value)
-	err = enc.Encode(block)
+	value, err := bs.codec.MarshalBlock(block)
 	if err != nil {
 		return err
 	}
+	var commitValue []byte
+	if commit != nil {
+		if commit.Height != height || commit.HeaderHash != hash {
+			return fmt.Errorf("%w: commit for height %d signs header %X, block at height %d has hash %X",
+				ErrCommitMismatch, commit.Height, commit.HeaderHash, height, hash)
+		}
+		commitValue, err = bs.codec.MarshalCommit(commit)
+		if err != nil {
+			return err
+		}
+	}
 
 	bs.mtx.Lock()
 	defer bs.mtx.Unlock()
-	// TODO(tzdybal): use transaction for consistency of DB
-	err = multierr.Append(err, bs.db.Set(key, value.Bytes()))
-	err = multierr.Append(err, bs.db.Set(ikey, hash[:]))
 
-	if block.Header.Height > bs.height {
-		bs.height = block.Header.Height
+	if height == 0 || (bs.base != 0 && (height > bs.height+1 || height+1 < bs.base)) {
+		return fmt.Errorf("%w: height %d, stored %d-%d", ErrNonContiguousHeight, height, bs.base, bs.height)
+	}
+
+	// block, index, commit, height and base are saved atomically, so crash can't leave block half-persisted
+	batch := bs.db.NewBatch()
+	defer batch.Discard()
+	err = multierr.Append(err, batch.Set(key, value))
+	err = multierr.Append(err, batch.Set(ikey, hash[:]))
+	if commit != nil {
+		err = multierr.Append(err, batch.Set(getCommitKey(height), commitValue))
+	}
+	if height > bs.height {
+		err = multierr.Append(err, batch.Set(heightKey[:], encodeHeight(height)))
+	}
+	if bs.base == 0 || height < bs.base {
+		err = multierr.Append(err, batch.Set(baseKey[:], encodeHeight(height)))
+	}
+	if err != nil {
+		return err
+	}
+	if err := batch.Commit(); err != nil {
+		return err
+	}
+
+	if height > bs.height {
+		bs.height = height
+	}
+	if bs.base == 0 || height < bs.base {
+		bs.base = height
+	}
+	return bs.evict()
+}
+
+// evict removes the oldest blocks, until store capacity is no longer exceeded or the oldest block is not evictable.
+// Blocks are deleted in batches of at most maxBatchDeletes blocks. mtx has to be held by the caller.
+func (bs *DefaultBlockStore) evict() error {
+	if bs.capacity == 0 {
+		return nil
 	}
 
-	return err
+	for bs.height-bs.base+1 > bs.capacity {
+		base, blocked, err := bs.evictBatch()
+		if err != nil {
+			return err
+		}
+		if base == bs.base {
+			return nil
+		}
+		bs.base = base
+		bs.evicted = base - 1
+		if blocked {
+			return nil
+		}
+	}
+	return nil
+}
+
+// evictBatch removes at most maxBatchDeletes oldest blocks, and returns new base. blocked is true if eviction
+// stopped at a block that is not evictable.
+func (bs *DefaultBlockStore) evictBatch() (base uint64, blocked bool, err error) {
+	batch := bs.db.NewBatch()
+	defer batch.Discard()
+
+	base = bs.base
+	for n := 0; bs.height-base+1 > bs.capacity && n < maxBatchDeletes; base, n = base+1, n+1 {
+		ikey := getIndexKey(base)
+		hash, getErr := bs.db.Get(ikey)
+		if errors.Is(getErr, ErrKeyNotFound) {
+			continue
+		}
+		if getErr != nil {
+			return 0, false, getErr
+		}
+		if bs.evictable != nil {
+			block, err := bs.loadBlockByHash(hash)
+			if err != nil {
+				return 0, false, err
+			}
+			if !bs.evictable(block) {
+				blocked = true
+				break
+			}
+		}
+		err = multierr.Append(err, batch.Delete(append(blockPrefix[:], hash...)))
+		err = multierr.Append(err, batch.Delete(ikey))
+		err = multierr.Append(err, batch.Delete(getCommitKey(base)))
+	}
+	if base == bs.base {
+		return base, blocked, nil
+	}
+	err = multierr.Append(err, batch.Set(baseKey[:], encodeHeight(base)))
+	err = multierr.Append(err, batch.Set(evictedKey[:], encodeHeight(base-1)))
+	if err != nil {
+		return 0, false, err
+	}
+	if err := batch.Commit(); err != nil {
+		return 0, false, err
+	}
+	return base, blocked, nil
 }
 
 // TODO(tzdybal): what is more common access pattern? by height or by hash?
 // currently, we're indexing height->hash, and store blocks by hash, but we might as well store by height
 // and index hash->height
 func (bs *DefaultBlockStore) LoadBlock(height uint64) (*types.Block, error) {
-	buf := make([]byte, 8)
-	binary.LittleEndian.PutUint64(buf, height)
-	ikey := append(indexPrefix[:], buf[:]...)
-
-	hash, err := bs.db.Get(ikey)
+	hash, err := bs.db.Get(getIndexKey(height))
 
+	if errors.Is(err, ErrKeyNotFound) && height <= bs.getEvicted() {
+		return nil, fmt.Errorf("%w: height %d", ErrBlockEvicted, height)
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	// TODO(tzdybal): any better way to convert slice to array?
-	var h [32]byte
-	copy(h[:], hash)
-	return bs.LoadBlockByHash(h)
+	return bs.loadBlockByHash(hash)
 }
 
 func (bs *DefaultBlockStore) LoadBlockByHash(hash [32]byte) (*types.Block, error) {
-	key := append(blockPrefix[:], hash[:]...)
+	return bs.loadBlockByHash(hash[:])
+}
+
+func (bs *DefaultBlockStore) loadBlockByHash(hash []byte) (*types.Block, error) {
+	key := append(blockPrefix[:], hash...)
 
 	blockData, err := bs.db.Get(key)
 
@@ -98,9 +329,8 @@ func (bs *DefaultBlockStore) LoadBlockByHash(hash [32]byte) (*types.Block, error
 		return nil, err
 	}
 
-	dec := gob.NewDecoder(bytes.NewReader(blockData))
 	var block types.Block
-	err = dec.Decode(&block)
+	err = bs.codec.UnmarshalBlock(blockData, &block)
 	if err != nil {
 		return nil, err
 	}
@@ -108,14 +338,111 @@ func (bs *DefaultBlockStore) LoadBlockByHash(hash [32]byte) (*types.Block, error
 	return &block, nil
 }
 
-// TODO(tzdybal): replace with proper hashing mechanism
-func getHash(block *types.Block) ([32]byte, error) {
-	var header bytes.Buffer
-	enc := gob.NewEncoder(&header)
-	err := enc.Encode(block.Header)
+// SaveCommit saves commit of the block at given height.
+//
+// ErrCommitMismatch is returned if commit is for different height, or if block at given height is already saved and
+// commit doesn't sign its header.
+func (bs *DefaultBlockStore) SaveCommit(height uint64, commit *types.Commit) error {
+	if commit.Height != height {
+		return fmt.Errorf("%w: commit height %d, expected %d", ErrCommitMismatch, commit.Height, height)
+	}
+	hash, err := bs.db.Get(getIndexKey(height))
+	if err != nil && !errors.Is(err, ErrKeyNotFound) {
+		return err
+	}
+	if err == nil && !bytes.Equal(hash, commit.HeaderHash[:]) {
+		return fmt.Errorf("%w: commit signs header %X, block at height %d has hash %X", ErrCommitMismatch, commit.HeaderHash, height, hash)
+	}
+
+	value, err := bs.codec.MarshalCommit(commit)
 	if err != nil {
-		return [32]byte{}, err
+		return err
 	}
+	return bs.db.Set(getCommitKey(height), value)
+}
+
+// LoadCommit returns commit of the block at given height.
+func (bs *DefaultBlockStore) LoadCommit(height uint64) (*types.Commit, error) {
+	value, err := bs.db.Get(getCommitKey(height))
+	if err != nil {
+		return nil, err
+	}
+
+	var commit types.Commit
+	err = bs.codec.UnmarshalCommit(value, &commit)
+	if err != nil {
+		return nil, err
+	}
+
+	return &commit, nil
+}
+
+// hasCommit returns true if commit of the block at given height is saved.
+func (bs *DefaultBlockStore) hasCommit(height uint64) (bool, error) {
+	_, err := bs.db.Get(getCommitKey(height))
+	if errors.Is(err, ErrKeyNotFound) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (bs *DefaultBlockStore) getEvicted() uint64 {
+	bs.mtx.RLock()
+	defer bs.mtx.RUnlock()
+	return bs.evicted
+}
+
+// loadHeight returns height persisted in KVStore, or 0 if store is empty.
+func (bs *DefaultBlockStore) loadHeight() (uint64, error) {
+	return loadOptionalHeight(bs.db, heightKey[:])
+}
+
+// loadOptionalHeight returns height persisted in KVStore under given key, or 0 if key doesn't exist.
+func loadOptionalHeight(db KVStore, key []byte) (uint64, error) {
+	value, err := db.Get(key)
+	if errors.Is(err, ErrKeyNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return decodeHeight(value), nil
+}
+
+// lowestIndexedHeight returns the lowest height in height index, or 0 if index is empty.
+//
+// Heights are encoded in little endian, so keys are not ordered by height and entire index is scanned.
+func lowestIndexedHeight(db KVStore) uint64 {
+	it := db.PrefixIterator(indexPrefix[:])
+	defer it.Discard()
+
+	var lowest uint64
+	for ; it.Valid(); it.Next() {
+		key := it.Key()
+		if len(key) != len(indexPrefix)+8 {
+			continue
+		}
+		if h := decodeHeight(key[len(indexPrefix):]); lowest == 0 || h < lowest {
+			lowest = h
+		}
+	}
+	return lowest
+}
+
+func getIndexKey(height uint64) []byte {
+	return append(indexPrefix[:], encodeHeight(height)...)
+}
+
+func getCommitKey(height uint64) []byte {
+	return append(commitPrefix[:], encodeHeight(height)...)
+}
+
+func encodeHeight(height uint64) []byte {
+	buf := make([]byte, 8)
+	binary.LittleEndian.PutUint64(buf, height)
+	return buf
+}
 
-	return sha256.Sum256(header.Bytes()), nil
+func decodeHeight(buf []byte) uint64 {
+	return binary.LittleEndian.Uint64(buf)
 }
@@ -1,9 +1,12 @@
 package store
 
 import (
+	"errors"
 	"math/rand"
 	"testing"
 
+	"github.com/lazyledger/lazyledger-core/libs/log"
+
 	"github.com/lazyledger/optimint/types"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -12,25 +15,36 @@ import (
 func TestBlockstoreHeight(t *testing.T) {
 	t.Parallel()
 	cases := []struct {
-		name     string
-		blocks   []*types.Block
-		expected uint64
+		name        string
+		blocks      []*types.Block
+		expected    uint64
+		expectedErr error
 	}{
-		{"single block", []*types.Block{getRandomBlock(1, 0)}, 1},
+		{"single block", []*types.Block{getRandomBlock(1, 0)}, 1, nil},
 		{"two consecutive blocks", []*types.Block{
 			getRandomBlock(1, 0),
 			getRandomBlock(2, 0),
-		}, 2},
+		}, 2, nil},
 		{"blocks out of order", []*types.Block{
 			getRandomBlock(2, 0),
 			getRandomBlock(3, 0),
 			getRandomBlock(1, 0),
-		}, 3},
+		}, 3, nil},
+		{"starting above 1", []*types.Block{
+			getRandomBlock(100, 0),
+			getRandomBlock(101, 0),
+		}, 101, nil},
 		{"with a gap", []*types.Block{
 			getRandomBlock(1, 0),
 			getRandomBlock(9, 0),
-			getRandomBlock(10, 0),
-		}, 10},
+		}, 1, ErrNonContiguousHeight},
+		{"below a gap", []*types.Block{
+			getRandomBlock(5, 0),
+			getRandomBlock(3, 0),
+		}, 5, ErrNonContiguousHeight},
+		{"zero height", []*types.Block{
+			getRandomBlock(0, 0),
+		}, 0, ErrNonContiguousHeight},
 	}
 
 	for _, c := range cases {
@@ -39,9 +53,14 @@ func TestBlockstoreHeight(t *testing.T) {
 			bstore := NewBlockStore()
 			assert.Equal(uint64(0), bstore.Height())
 
+			var err error
 			for _, block := range c.blocks {
-				err := bstore.SaveBlock(block)
+				err = bstore.SaveBlock(block)
+			}
+			if c.expectedErr == nil {
 				assert.NoError(err)
+			} else {
+				assert.ErrorIs(err, c.expectedErr)
 			}
 
 			assert.Equal(c.expected, bstore.Height())
@@ -91,6 +110,241 @@ func TestBlockstoreLoad(t *testing.T) {
 	}
 }
 
+func TestOpenBlockStore(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name           string
+		heights        []uint64
+		missing        uint64
+		missingCommit  uint64
+		expectedHeight uint64
+		expectedErr    error
+		// gaps between the lowest and the highest block are detected only by Verify
+		verifyErr error
+	}{
+		{"empty store", nil, 0, 0, 0, nil, nil},
+		{"contiguous blocks", []uint64{1, 2, 3, 4, 5}, 0, 0, 5, nil, nil},
+		{"starting above 1", []uint64{100, 101, 102}, 0, 0, 102, nil, nil},
+		{"with a gap", []uint64{1, 2, 3, 4, 5}, 3, 0, 5, nil, ErrStoreGap},
+		{"missing first block", []uint64{100, 101, 102}, 100, 0, 0, ErrStoreGap, nil},
+		{"missing last block", []uint64{100, 101, 102}, 102, 0, 0, ErrStoreGap, nil},
+		{"missing first commit", []uint64{1, 2, 3}, 0, 1, 3, nil, nil},
+		{"missing commit", []uint64{1, 2, 3}, 0, 2, 3, nil, ErrStoreGap},
+		{"missing last commit", []uint64{1, 2, 3}, 0, 3, 0, ErrStoreGap, nil},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			kv := NewInMemoryKVStore()
+			bstore, err := OpenBlockStore(kv, log.TestingLogger())
+			require.NoError(err)
+			for _, h := range c.heights {
+				block := getRandomBlock(h, 10)
+				require.NoError(bstore.SaveBlockWithCommit(block, &types.Commit{Height: h, HeaderHash: block.Header.Hash()}))
+			}
+			if c.missing != 0 {
+				require.NoError(kv.Delete(getIndexKey(c.missing)))
+			}
+			if c.missingCommit != 0 {
+				require.NoError(kv.Delete(getCommitKey(c.missingCommit)))
+			}
+
+			reopened, err := OpenBlockStore(kv, log.TestingLogger())
+			if c.expectedErr != nil {
+				assert.ErrorIs(err, c.expectedErr)
+				assert.Nil(reopened)
+
+				// blocks are not deleted
+				for _, h := range c.heights {
+					if h != c.missing {
+						_, err := kv.Get(getIndexKey(h))
+						assert.NoError(err)
+					}
+				}
+				return
+			}
+			require.NoError(err)
+			assert.Equal(c.expectedHeight, reopened.Height())
+			for _, h := range c.heights {
+				if h == c.missing {
+					continue
+				}
+				block, err := reopened.LoadBlock(h)
+				assert.NoError(err)
+				assert.NotNil(block)
+			}
+			if c.verifyErr != nil {
+				assert.ErrorIs(Verify(reopened), c.verifyErr)
+			} else {
+				assert.NoError(Verify(reopened))
+			}
+		})
+	}
+}
+
+func TestBlockStoreCapacity(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+	require := require.New(t)
+
+	// blocks up to submitted height can be evicted
+	var submitted uint64
+	kv := NewInMemoryKVStore()
+	bstore, err := OpenBlockStore(kv, log.TestingLogger(), WithCapacity(2, func(block *types.Block) bool {
+		return block.Header.Height <= submitted
+	}))
+	require.NoError(err)
+
+	// assertStored checks that exactly blocks from given range are stored
+	assertStored := func(bstore BlockStore, from, to uint64) {
+		for h := uint64(1); h <= bstore.Height(); h++ {
+			block, err := bstore.LoadBlock(h)
+			if h >= from && h <= to {
+				assert.NoError(err, h)
+				assert.NotNil(block)
+			} else {
+				assert.ErrorIs(err, ErrBlockEvicted, h)
+				assert.Nil(block)
+				_, err = bstore.LoadCommit(h)
+				assert.ErrorIs(err, ErrKeyNotFound)
+			}
+		}
+	}
+
+	blocks := make(map[uint64]*types.Block)
+	save := func(from, to uint64) {
+		for h := from; h <= to; h++ {
+			blocks[h] = getRandomBlock(h, 10)
+			require.NoError(bstore.SaveBlock(blocks[h]))
+			require.NoError(bstore.SaveCommit(h, &types.Commit{Height: h, HeaderHash: blocks[h].Header.Hash()}))
+		}
+	}
+
+	// blocks are not submitted, so capacity is exceeded
+	save(1, 3)
+	assertStored(bstore, 1, 3)
+
+	// oldest blocks are evicted first
+	submitted = 5
+	save(4, 5)
+	assertStored(bstore, 4, 5)
+
+	// eviction stops at first block that is not evictable
+	save(6, 8)
+	assertStored(bstore, 6, 8)
+	submitted = 7
+	save(9, 9)
+	assertStored(bstore, 8, 9)
+
+	// evicted blocks are removed from KVStore
+	for h := uint64(1); h <= 7; h++ {
+		hash := blocks[h].Header.Hash()
+		_, err := kv.Get(append(blockPrefix[:], hash[:]...))
+		assert.ErrorIs(err, ErrKeyNotFound)
+		_, err = bstore.LoadBlockByHash(hash)
+		assert.ErrorIs(err, ErrKeyNotFound)
+	}
+
+	// evicted heights are persisted
+	reopened, err := OpenBlockStore(kv, log.TestingLogger())
+	require.NoError(err)
+	assert.Equal(uint64(9), reopened.Height())
+	assertStored(reopened, 8, 9)
+}
+
+func TestSaveBlockCrash(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+	require := require.New(t)
+
+	kv := &crashingKV{KVStore: NewInMemoryKVStore()}
+	bstore, err := OpenBlockStore(kv, log.TestingLogger())
+	require.NoError(err)
+	first := getRandomBlock(1, 10)
+	require.NoError(bstore.SaveBlock(first))
+
+	kv.crash = true
+	second := getRandomBlock(2, 10)
+	assert.ErrorIs(bstore.SaveBlock(second), errCrash)
+	assert.ErrorIs(bstore.SaveBlockWithCommit(second, &types.Commit{Height: 2, HeaderHash: second.Header.Hash()}), errCrash)
+	assert.Equal(uint64(1), bstore.Height())
+
+	// nothing was persisted, so block can't be loaded by height or hash
+	kv.crash = false
+	reopened, err := OpenBlockStore(kv, log.TestingLogger())
+	require.NoError(err)
+	for _, bs := range []BlockStore{bstore, reopened} {
+		assert.Equal(uint64(1), bs.Height())
+		block, err := bs.LoadBlock(1)
+		require.NoError(err)
+		assert.Equal(first, block)
+		_, err = bs.LoadBlock(2)
+		assert.ErrorIs(err, ErrKeyNotFound)
+		_, err = bs.LoadBlockByHash(second.Header.Hash())
+		assert.ErrorIs(err, ErrKeyNotFound)
+		_, err = bs.LoadCommit(2)
+		assert.ErrorIs(err, ErrKeyNotFound)
+	}
+}
+
+func TestBlockStoreEvictionBatches(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+	require := require.New(t)
+
+	// more than maxBatchDeletes blocks are evicted at once
+	const n = 2*maxBatchDeletes + 10
+	evictable := false
+	kv := NewInMemoryKVStore()
+	bstore, err := OpenBlockStore(kv, log.TestingLogger(), WithCapacity(1, func(*types.Block) bool { return evictable }))
+	require.NoError(err)
+	for h := uint64(1); h <= n; h++ {
+		require.NoError(bstore.SaveBlock(&types.Block{Header: types.Header{Height: h}}))
+	}
+	evictable = true
+	require.NoError(bstore.SaveBlock(&types.Block{Header: types.Header{Height: n + 1}}))
+
+	_, err = bstore.LoadBlock(n)
+	assert.ErrorIs(err, ErrBlockEvicted)
+	_, err = bstore.LoadBlock(n + 1)
+	assert.NoError(err)
+
+	reopened, err := OpenBlockStore(kv, log.TestingLogger())
+	require.NoError(err)
+	_, err = reopened.LoadBlock(1)
+	assert.ErrorIs(err, ErrBlockEvicted)
+	_, err = reopened.LoadBlock(n + 1)
+	assert.NoError(err)
+}
+
+var errCrash = errors.New("simulated crash")
+
+// crashingKV simulates a crash before batch is committed, when crash is set.
+type crashingKV struct {
+	KVStore
+	crash bool
+}
+
+func (kv *crashingKV) NewBatch() Batch {
+	return &crashingBatch{Batch: kv.KVStore.NewBatch(), crash: kv.crash}
+}
+
+type crashingBatch struct {
+	Batch
+	crash bool
+}
+
+func (b *crashingBatch) Commit() error {
+	if b.crash {
+		b.Discard()
+		return errCrash
+	}
+	return b.Batch.Commit()
+}
+
 func getRandomBlock(height uint64, nTxs int) *types.Block {
 	block := &types.Block{
 		Header: types.Header{
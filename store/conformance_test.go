@@ -0,0 +1,97 @@
+package store_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/lazyledger/lazyledger-core/libs/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lazyledger/optimint/store"
+	"github.com/lazyledger/optimint/store/storetest"
+	"github.com/lazyledger/optimint/types"
+)
+
+func TestInMemoryBlockStore(t *testing.T) {
+	storetest.TestBlockStore(t, func(t *testing.T) store.BlockStore {
+		return store.NewBlockStore()
+	})
+}
+
+func TestDiskBlockStore(t *testing.T) {
+	storetest.TestBlockStore(t, func(t *testing.T) store.BlockStore {
+		require := require.New(t)
+
+		dir, err := ioutil.TempDir("", "blockstore")
+		require.NoError(err)
+		kv, err := store.NewDiskKVStore(dir)
+		require.NoError(err)
+		t.Cleanup(func() {
+			assert.NoError(t, kv.(*store.BadgerKV).Close())
+			assert.NoError(t, os.RemoveAll(dir))
+		})
+
+		bs, err := store.OpenBlockStore(kv, log.TestingLogger())
+		require.NoError(err)
+		return bs
+	})
+}
+
+func TestBlockStoreWithCodec(t *testing.T) {
+	storetest.TestBlockStore(t, func(t *testing.T) store.BlockStore {
+		return store.NewBlockStore(store.WithCodec(jsonCodec{}))
+	})
+
+	t.Run("RoundTrip", func(t *testing.T) {
+		assert := assert.New(t)
+		require := require.New(t)
+
+		kv := store.NewInMemoryKVStore()
+		bs, err := store.OpenBlockStore(kv, log.TestingLogger(), store.WithCodec(jsonCodec{}))
+		require.NoError(err)
+
+		block := &types.Block{
+			Header:     types.Header{Height: 1, ProposerAddress: []byte("proposer")},
+			Data:       types.Data{Txs: types.Txs{types.Tx("tx1"), types.Tx("tx2")}},
+			LastCommit: &types.Commit{Height: 1, Signatures: []types.Signature{{1, 2, 3}}},
+		}
+		require.NoError(bs.SaveBlock(block))
+
+		loaded, err := bs.LoadBlock(1)
+		require.NoError(err)
+		assert.Equal(block, loaded)
+
+		// blocks are stored in JSON
+		hash := block.Header.Hash()
+		data, err := kv.Get(append([]byte{1}, hash[:]...))
+		require.NoError(err)
+		assert.True(json.Valid(data))
+
+		// default codec can't decode blocks stored with other codec
+		bs, err = store.OpenBlockStore(kv, log.TestingLogger())
+		require.NoError(err)
+		_, err = bs.LoadBlock(1)
+		assert.Error(err)
+	})
+}
+
+type jsonCodec struct{}
+
+func (jsonCodec) MarshalBlock(block *types.Block) ([]byte, error) {
+	return json.Marshal(block)
+}
+
+func (jsonCodec) UnmarshalBlock(data []byte, block *types.Block) error {
+	return json.Unmarshal(data, block)
+}
+
+func (jsonCodec) MarshalCommit(commit *types.Commit) ([]byte, error) {
+	return json.Marshal(commit)
+}
+
+func (jsonCodec) UnmarshalCommit(data []byte, commit *types.Commit) error {
+	return json.Unmarshal(data, commit)
+}
@@ -0,0 +1,25 @@
+package store
+
+import "errors"
+
+var (
+	// ErrKeyNotFound is returned by KVStore when key doesn't exist.
+	ErrKeyNotFound = errors.New("key not found")
+	// ErrInvalidLinkage is returned when imported block doesn't link to previous block.
+	ErrInvalidLinkage = errors.New("block doesn't link to previous block")
+	// ErrFrameTooLarge is returned when length prefix of exported block exceeds maxFrameSize.
+	ErrFrameTooLarge = errors.New("block frame too large")
+	// ErrUnsupportedVersion is returned when store was written by newer, incompatible version, or in format that
+	// can't be migrated.
+	ErrUnsupportedVersion = errors.New("unsupported store version")
+	// ErrNotIndexed is returned when searching by event attribute that is not indexed.
+	ErrNotIndexed = errors.New("event attribute not indexed")
+	// ErrCommitMismatch is returned when saved commit doesn't sign the block at given height.
+	ErrCommitMismatch = errors.New("commit doesn't match block")
+	// ErrBlockEvicted is returned when loading block that was evicted from store with limited capacity.
+	ErrBlockEvicted = errors.New("block evicted from store")
+	// ErrNonContiguousHeight is returned when saved block would create a gap in stored heights.
+	ErrNonContiguousHeight = errors.New("block height not contiguous with stored blocks")
+	// ErrStoreGap is returned when opened or verified store has a gap in stored heights.
+	ErrStoreGap = errors.New("gap in stored blocks")
+)
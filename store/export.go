@@ -0,0 +1,97 @@
+package store
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/lazyledger/optimint/types"
+)
+
+// maxFrameSize limits the size of a single block read by Import, to avoid allocating arbitrary amount of memory.
+const maxFrameSize = 64 * 1024 * 1024
+
+// Export writes all blocks from the store into w, starting from the lowest stored height (see BlockStore.Base).
+//
+// Export format is a sequence of frames. Each frame contains single block in binary form, prefixed with
+// its length encoded as unsigned varint.
+func Export(bs BlockStore, w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	lenBuf := make([]byte, binary.MaxVarintLen64)
+	for height := bs.Base(); height > 0 && height <= bs.Height(); height++ {
+		block, err := bs.LoadBlock(height)
+		if err != nil {
+			return fmt.Errorf("failed to load block at height %d: %w", height, err)
+		}
+		data, err := block.MarshalBinary()
+		if err != nil {
+			return fmt.Errorf("failed to serialize block at height %d: %w", height, err)
+		}
+		n := binary.PutUvarint(lenBuf, uint64(len(data)))
+		if _, err := bw.Write(lenBuf[:n]); err != nil {
+			return err
+		}
+		if _, err := bw.Write(data); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// Import reads blocks written by Export from r and saves them in the store.
+//
+// If the store is empty, first imported block can be at any height (export of store with evicted blocks, or genesis
+// initial height other than 1 starts above 1). Otherwise it has to be the direct successor of the latest block in the
+// store. Every block has to be at consecutive height and link to the previous block by LastHeaderHash.
+func Import(bs BlockStore, r io.Reader) error {
+	br := bufio.NewReader(r)
+
+	var prevHash [32]byte
+	// expectedHeight is 0 until the first block is read, if store is empty
+	var expectedHeight uint64
+	if height := bs.Height(); height > 0 {
+		prev, err := bs.LoadBlock(height)
+		if err != nil {
+			return fmt.Errorf("failed to load latest block: %w", err)
+		}
+		prevHash = prev.Header.Hash()
+		expectedHeight = height + 1
+	}
+
+	for {
+		size, err := binary.ReadUvarint(br)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read frame length: %w", err)
+		}
+		if size > maxFrameSize {
+			return fmt.Errorf("%w: %d bytes", ErrFrameTooLarge, size)
+		}
+		data := make([]byte, size)
+		if _, err := io.ReadFull(br, data); err != nil {
+			return fmt.Errorf("failed to read block after height %d: %w", bs.Height(), err)
+		}
+
+		var block types.Block
+		if err := block.UnmarshalBinary(data); err != nil {
+			return fmt.Errorf("failed to deserialize block after height %d: %w", bs.Height(), err)
+		}
+		if expectedHeight == 0 {
+			expectedHeight = block.Header.Height
+		} else if block.Header.Height != expectedHeight {
+			return fmt.Errorf("%w: expected height %d, got %d", ErrInvalidLinkage, expectedHeight, block.Header.Height)
+		} else if block.Header.LastHeaderHash != prevHash {
+			return fmt.Errorf("%w: invalid LastHeaderHash at height %d", ErrInvalidLinkage, expectedHeight)
+		}
+
+		if err := bs.SaveBlock(&block); err != nil {
+			return fmt.Errorf("failed to save block at height %d: %w", expectedHeight, err)
+		}
+		prevHash = block.Header.Hash()
+		expectedHeight++
+	}
+}
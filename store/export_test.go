@@ -0,0 +1,140 @@
+package store
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lazyledger/optimint/types"
+)
+
+func TestExportImport(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+	require := require.New(t)
+
+	src := NewBlockStore()
+	blocks := getLinkedBlocks(10)
+	for _, block := range blocks {
+		require.NoError(src.SaveBlock(block))
+	}
+
+	var buf bytes.Buffer
+	err := Export(src, &buf)
+	require.NoError(err)
+
+	dst := NewBlockStore()
+	err = Import(dst, &buf)
+	require.NoError(err)
+
+	assert.Equal(src.Height(), dst.Height())
+	for _, expected := range blocks {
+		block, err := dst.LoadBlock(expected.Header.Height)
+		assert.NoError(err)
+		assert.Equal(expected, block)
+	}
+}
+
+func TestExportImportFromBase(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name    string
+		src     BlockStore
+		blocks  []*types.Block
+		base    uint64
+		evicted uint64
+	}{
+		{"initial height above 1", NewBlockStore(), getLinkedBlocksFrom(5, 6), 5, 0},
+		{"evicted blocks", NewBlockStore(WithCapacity(4, nil)), getLinkedBlocks(10), 7, 6},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			for _, block := range c.blocks {
+				require.NoError(c.src.SaveBlock(block))
+			}
+			require.Equal(c.base, c.src.Base())
+
+			var buf bytes.Buffer
+			require.NoError(Export(c.src, &buf))
+
+			dst := NewBlockStore()
+			require.NoError(Import(dst, &buf))
+
+			assert.Equal(c.base, dst.Base())
+			assert.Equal(c.src.Height(), dst.Height())
+			for _, expected := range c.blocks {
+				block, err := dst.LoadBlock(expected.Header.Height)
+				if expected.Header.Height <= c.evicted {
+					assert.ErrorIs(err, ErrKeyNotFound)
+					continue
+				}
+				assert.NoError(err)
+				assert.Equal(expected, block)
+			}
+		})
+	}
+}
+
+func TestImportInvalidLinkage(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name   string
+		mangle func([]*types.Block)
+	}{
+		{"invalid hash", func(blocks []*types.Block) {
+			blocks[3].Header.LastHeaderHash[0]++
+		}},
+		{"missing block", func(blocks []*types.Block) {
+			copy(blocks[3:], blocks[4:])
+		}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			blocks := getLinkedBlocks(5)
+			c.mangle(blocks)
+
+			// blocks are framed manually, because Export doesn't allow gaps
+			var buf bytes.Buffer
+			for _, block := range blocks {
+				data, err := block.MarshalBinary()
+				require.NoError(err)
+				lenBuf := make([]byte, binary.MaxVarintLen64)
+				n := binary.PutUvarint(lenBuf, uint64(len(data)))
+				buf.Write(lenBuf[:n])
+				buf.Write(data)
+			}
+
+			dst := NewBlockStore()
+			err := Import(dst, &buf)
+			assert.ErrorIs(err, ErrInvalidLinkage)
+		})
+	}
+}
+
+func getLinkedBlocks(n int) []*types.Block {
+	return getLinkedBlocksFrom(1, n)
+}
+
+func getLinkedBlocksFrom(height uint64, n int) []*types.Block {
+	blocks := make([]*types.Block, n)
+	for i := range blocks {
+		blocks[i] = getRandomBlock(height+uint64(i), 10)
+		if i > 0 {
+			blocks[i].Header.LastHeaderHash = blocks[i-1].Header.Hash()
+		}
+	}
+	return blocks
+}
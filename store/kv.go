@@ -6,9 +6,45 @@ import "github.com/dgraph-io/badger/v3"
 //
 // KVStore MUST be thread safe.
 type KVStore interface {
-	Get(key []byte) ([]byte, error)     // Get gets the value for a key.
+	Get(key []byte) ([]byte, error)     // Get gets the value for a key. ErrKeyNotFound is returned if key doesn't exist.
 	Set(key []byte, value []byte) error // Set updates the value for a key.
 	Delete(key []byte) error            // Delete deletes a key.
+	NewBatch() Batch                    // NewBatch creates a Batch for atomic updates.
+
+	// PrefixIterator returns Iterator over all keys with given prefix, in lexicographical order.
+	PrefixIterator(prefix []byte) Iterator
+}
+
+// Iterator iterates over a consistent snapshot of KVStore.
+//
+// Iterator is not thread safe. Discard has to be called when iterator is no longer used.
+type Iterator interface {
+	Valid() bool            // Valid returns false when iteration is finished.
+	Next()                  // Next moves iterator to the next key.
+	Key() []byte            // Key returns copy of the current key.
+	Value() ([]byte, error) // Value returns copy of the current value.
+	Discard()               // Discard releases resources of the iterator.
+}
+
+// Batch groups updates of KVStore, to be applied atomically.
+//
+// Batch is not thread safe. It can't be used after Commit or Discard.
+type Batch interface {
+	Set(key []byte, value []byte) error // Set updates the value for a key.
+	Delete(key []byte) error            // Delete deletes a key.
+	Commit() error                      // Commit applies all updates.
+	Discard()                           // Discard drops all updates. It's safe to call Discard after Commit.
+}
+
+// NewDiskKVStore returns KVStore persisting data in dir.
+func NewDiskKVStore(dir string) (KVStore, error) {
+	db, err := badger.Open(badger.DefaultOptions(dir))
+	if err != nil {
+		return nil, err
+	}
+	return &BadgerKV{
+		db: db,
+	}, nil
 }
 
 func NewInMemoryKVStore() KVStore {
@@ -0,0 +1,95 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/lazyledger/optimint/log"
+)
+
+// StoreVersion is the version of on-disk format written by this version of the store.
+//
+// Version history:
+//  1. blocks indexed by height, height not persisted (no version marker); only in-memory stores used this format,
+//     so it's never found on disk and there is no migration from it
+//  2. height persisted under heightKey
+//  3. lowest stored height persisted under baseKey, highest evicted height under evictedKey
+const StoreVersion uint64 = 3
+
+var versionKey = [1]byte{4}
+
+// migrations[v] upgrades store from version v to v+1.
+var migrations = map[uint64]func(db KVStore) error{
+	2: migrateV2ToV3,
+}
+
+// migrate upgrades format of the store to StoreVersion and persists version marker.
+func migrate(db KVStore, logger log.Logger) error {
+	version, err := loadVersion(db)
+	if err != nil {
+		return err
+	}
+	if version > StoreVersion {
+		return fmt.Errorf("%w: %d (supported: %d)", ErrUnsupportedVersion, version, StoreVersion)
+	}
+
+	for ; version < StoreVersion; version++ {
+		migration, ok := migrations[version]
+		if !ok {
+			return fmt.Errorf("%w: %d can't be migrated", ErrUnsupportedVersion, version)
+		}
+		logger.Info("migrating store", "from", version, "to", version+1)
+		if err := migration(db); err != nil {
+			return fmt.Errorf("failed to migrate store from version %d: %w", version, err)
+		}
+		if err := db.Set(versionKey[:], encodeHeight(version+1)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadVersion returns version of the store format.
+//
+// Stores without version marker are either empty (and get current version) or written in version 2, before
+// versioning was added. Stores with blocks, but without height, are rejected with ErrUnsupportedVersion.
+func loadVersion(db KVStore) (uint64, error) {
+	value, err := db.Get(versionKey[:])
+	if err == nil {
+		return decodeHeight(value), nil
+	}
+	if !errors.Is(err, ErrKeyNotFound) {
+		return 0, err
+	}
+
+	if _, err := db.Get(heightKey[:]); err == nil {
+		return 2, nil
+	} else if !errors.Is(err, ErrKeyNotFound) {
+		return 0, err
+	}
+	// chains can start at any height, so any indexed block without height indicates version 1
+	if lowestIndexedHeight(db) > 0 {
+		return 0, fmt.Errorf("%w: blocks stored without height", ErrUnsupportedVersion)
+	}
+
+	// empty store
+	return StoreVersion, db.Set(versionKey[:], encodeHeight(StoreVersion))
+}
+
+// migrateV2ToV3 persists the lowest stored height, by scanning height index.
+//
+// In version 2, baseKey was written only when blocks were evicted, so it already contains the lowest stored height
+// and blocks below it were evicted.
+func migrateV2ToV3(db KVStore) error {
+	base, err := loadOptionalHeight(db, baseKey[:])
+	if err != nil {
+		return err
+	}
+	if base > 0 {
+		return db.Set(evictedKey[:], encodeHeight(base-1))
+	}
+	if base = lowestIndexedHeight(db); base == 0 {
+		return nil
+	}
+	return db.Set(baseKey[:], encodeHeight(base))
+}
@@ -0,0 +1,85 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/lazyledger/lazyledger-core/libs/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrateV2ToV3(t *testing.T) {
+	cases := []struct {
+		name            string
+		base            uint64
+		expectedEvicted uint64
+	}{
+		{"no evicted blocks", 0, 0},
+		{"evicted blocks", 101, 100},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			// prepare store in version 2 format: baseKey is written only after eviction
+			kv := NewInMemoryKVStore()
+			v2 := newBlockStore(kv, nil)
+			for h := uint64(100); h <= 105; h++ {
+				require.NoError(v2.SaveBlock(getRandomBlock(h, 10)))
+			}
+			require.NoError(kv.Delete(baseKey[:]))
+			if c.base > 0 {
+				require.NoError(kv.Delete(getIndexKey(100)))
+				require.NoError(kv.Set(baseKey[:], encodeHeight(c.base)))
+			}
+			require.NoError(kv.Set(versionKey[:], encodeHeight(2)))
+
+			bstore, err := OpenBlockStore(kv, log.TestingLogger())
+			require.NoError(err)
+			assert.Equal(uint64(105), bstore.Height())
+
+			evicted, err := loadOptionalHeight(kv, evictedKey[:])
+			require.NoError(err)
+			assert.Equal(c.expectedEvicted, evicted)
+			_, err = bstore.LoadBlock(101)
+			assert.NoError(err)
+			_, err = bstore.LoadBlock(100)
+			if c.expectedEvicted > 0 {
+				assert.ErrorIs(err, ErrBlockEvicted)
+			} else {
+				assert.NoError(err)
+			}
+		})
+	}
+}
+
+func TestStoreVersion(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	// empty store gets current version
+	kv := NewInMemoryKVStore()
+	_, err := OpenBlockStore(kv, log.TestingLogger())
+	require.NoError(err)
+	value, err := kv.Get(versionKey[:])
+	require.NoError(err)
+	assert.Equal(StoreVersion, decodeHeight(value))
+
+	// newer versions are rejected
+	require.NoError(kv.Set(versionKey[:], encodeHeight(StoreVersion+1)))
+	bstore, err := OpenBlockStore(kv, log.TestingLogger())
+	assert.ErrorIs(err, ErrUnsupportedVersion)
+	assert.Nil(bstore)
+
+	// version 1 format (blocks without height) is not migrated
+	kv = NewInMemoryKVStore()
+	v1 := newBlockStore(kv, nil)
+	require.NoError(v1.SaveBlock(getRandomBlock(1, 10)))
+	require.NoError(kv.Delete(heightKey[:]))
+	require.NoError(kv.Delete(baseKey[:]))
+	bstore, err = OpenBlockStore(kv, log.TestingLogger())
+	assert.ErrorIs(err, ErrUnsupportedVersion)
+	assert.Nil(bstore)
+}
@@ -0,0 +1,252 @@
+// Package storetest provides conformance tests for store.BlockStore implementations.
+package storetest
+
+import (
+	"crypto/rand"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lazyledger/optimint/store"
+	"github.com/lazyledger/optimint/types"
+)
+
+// Factory returns new, empty BlockStore. It's called once for every test case.
+//
+// Factory is responsible for releasing resources used by the store (for example, with t.Cleanup).
+type Factory func(t *testing.T) store.BlockStore
+
+// TestBlockStore verifies that BlockStore implementation returned by factory follows the BlockStore contract:
+//   - Height is the highest height of saved block (it never decreases, and it's 0 for empty store),
+//   - Base is the lowest height of saved block (0 for empty store),
+//   - stored heights are contiguous: saving block that would create a gap returns store.ErrNonContiguousHeight,
+//     but the first block can be saved at any height (for example, genesis initial height),
+//   - saved block can be loaded by height and by header hash,
+//   - loading missing block returns store.ErrKeyNotFound,
+//   - saving block at already used height replaces the block at this height, without changing Height,
+//   - saved commit can be loaded by height, and it has to sign the block saved at the same height,
+//   - block saved with commit can be loaded together with the commit; if commit doesn't sign the block,
+//     store.ErrCommitMismatch is returned and neither is saved.
+func TestBlockStore(t *testing.T, factory Factory) {
+	t.Run("Height", func(t *testing.T) { testHeight(t, factory) })
+	t.Run("Load", func(t *testing.T) { testLoad(t, factory) })
+	t.Run("LoadByHash", func(t *testing.T) { testLoadByHash(t, factory) })
+	t.Run("Missing", func(t *testing.T) { testMissing(t, factory) })
+	t.Run("Overwrite", func(t *testing.T) { testOverwrite(t, factory) })
+	t.Run("Commit", func(t *testing.T) { testCommit(t, factory) })
+	t.Run("BlockWithCommit", func(t *testing.T) { testBlockWithCommit(t, factory) })
+}
+
+func testHeight(t *testing.T, factory Factory) {
+	cases := []struct {
+		name     string
+		heights  []uint64
+		expected uint64
+		base     uint64
+	}{
+		{"empty store", nil, 0, 0},
+		{"single block", []uint64{1}, 1, 1},
+		{"consecutive blocks", []uint64{1, 2, 3}, 3, 1},
+		{"blocks out of order", []uint64{2, 3, 1}, 3, 1},
+		{"starting above 1", []uint64{100, 101}, 101, 100},
+		{"lower height after higher", []uint64{5, 4}, 5, 4},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			bs := factory(t)
+			assert.Equal(uint64(0), bs.Height())
+			assert.Equal(uint64(0), bs.Base())
+			for _, h := range c.heights {
+				require.NoError(bs.SaveBlock(randomBlock(h, 2)))
+			}
+			assert.Equal(c.expected, bs.Height())
+			assert.Equal(c.base, bs.Base())
+		})
+	}
+
+	t.Run("with a gap", func(t *testing.T) {
+		assert := assert.New(t)
+		require := require.New(t)
+
+		bs := factory(t)
+		require.NoError(bs.SaveBlock(randomBlock(5, 1)))
+		assert.ErrorIs(bs.SaveBlock(randomBlock(7, 1)), store.ErrNonContiguousHeight)
+		assert.ErrorIs(bs.SaveBlock(randomBlock(3, 1)), store.ErrNonContiguousHeight)
+		assert.Equal(uint64(5), bs.Height())
+		_, err := bs.LoadBlock(7)
+		assert.ErrorIs(err, store.ErrKeyNotFound)
+	})
+}
+
+func testLoad(t *testing.T, factory Factory) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	bs := factory(t)
+	blocks := []*types.Block{randomBlock(2, 3), randomBlock(3, 1), randomBlock(1, 10), randomBlock(4, 1)}
+	for _, b := range blocks {
+		require.NoError(bs.SaveBlock(b))
+	}
+	for _, expected := range blocks {
+		block, err := bs.LoadBlock(expected.Header.Height)
+		require.NoError(err)
+		assert.Equal(expected, block)
+	}
+}
+
+func testLoadByHash(t *testing.T, factory Factory) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	bs := factory(t)
+	blocks := []*types.Block{randomBlock(1, 1), randomBlock(2, 5)}
+	for _, b := range blocks {
+		require.NoError(bs.SaveBlock(b))
+	}
+	for _, expected := range blocks {
+		block, err := bs.LoadBlockByHash(expected.Header.Hash())
+		require.NoError(err)
+		assert.Equal(expected, block)
+	}
+}
+
+func testMissing(t *testing.T, factory Factory) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	bs := factory(t)
+	_, err := bs.LoadBlock(1)
+	assert.True(errors.Is(err, store.ErrKeyNotFound), err)
+
+	require.NoError(bs.SaveBlock(randomBlock(1, 1)))
+	_, err = bs.LoadBlock(2)
+	assert.True(errors.Is(err, store.ErrKeyNotFound), err)
+	_, err = bs.LoadBlockByHash(randomBlock(1, 2).Header.Hash())
+	assert.True(errors.Is(err, store.ErrKeyNotFound), err)
+}
+
+func testOverwrite(t *testing.T, factory Factory) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	bs := factory(t)
+	for h := uint64(1); h <= 3; h++ {
+		require.NoError(bs.SaveBlock(randomBlock(h, 1)))
+	}
+	replacement := randomBlock(2, 4)
+	require.NoError(bs.SaveBlock(replacement))
+
+	assert.Equal(uint64(3), bs.Height())
+	block, err := bs.LoadBlock(2)
+	require.NoError(err)
+	assert.Equal(replacement, block)
+}
+
+func testCommit(t *testing.T, factory Factory) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	bs := factory(t)
+	_, err := bs.LoadCommit(1)
+	assert.True(errors.Is(err, store.ErrKeyNotFound), err)
+
+	blocks := []*types.Block{randomBlock(1, 1), randomBlock(2, 1)}
+	commits := make([]*types.Commit, len(blocks))
+	for i, b := range blocks {
+		require.NoError(bs.SaveBlock(b))
+		commits[i] = &types.Commit{Height: b.Header.Height, HeaderHash: b.Header.Hash(), Signatures: []types.Signature{randomBytes(64)}}
+		require.NoError(bs.SaveCommit(b.Header.Height, commits[i]))
+	}
+	for i, expected := range commits {
+		commit, err := bs.LoadCommit(uint64(i + 1))
+		require.NoError(err)
+		assert.Equal(expected, commit)
+		block, err := bs.LoadBlockByHash(commit.HeaderHash)
+		require.NoError(err)
+		assert.Equal(blocks[i], block)
+	}
+
+	// commit can be saved before the block
+	commit := &types.Commit{Height: 3, HeaderHash: randomBlock(3, 1).Header.Hash(), Signatures: []types.Signature{randomBytes(64)}}
+	require.NoError(bs.SaveCommit(3, commit))
+	loaded, err := bs.LoadCommit(3)
+	require.NoError(err)
+	assert.Equal(commit, loaded)
+
+	// commit has to match height and block
+	err = bs.SaveCommit(1, commits[1])
+	assert.True(errors.Is(err, store.ErrCommitMismatch), err)
+	err = bs.SaveCommit(2, &types.Commit{Height: 2, HeaderHash: blocks[0].Header.Hash()})
+	assert.True(errors.Is(err, store.ErrCommitMismatch), err)
+	commit, err = bs.LoadCommit(2)
+	require.NoError(err)
+	assert.Equal(commits[1], commit)
+}
+
+func testBlockWithCommit(t *testing.T, factory Factory) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	bs := factory(t)
+	block := randomBlock(1, 1)
+	commit := &types.Commit{Height: 1, HeaderHash: block.Header.Hash(), Signatures: []types.Signature{randomBytes(64)}}
+	require.NoError(bs.SaveBlockWithCommit(block, commit))
+	assert.Equal(uint64(1), bs.Height())
+	loadedBlock, err := bs.LoadBlock(1)
+	require.NoError(err)
+	assert.Equal(block, loadedBlock)
+	loadedCommit, err := bs.LoadCommit(1)
+	require.NoError(err)
+	assert.Equal(commit, loadedCommit)
+
+	// commit has to match height and block
+	next := randomBlock(2, 1)
+	for _, invalid := range []*types.Commit{
+		{Height: 3, HeaderHash: next.Header.Hash()},
+		{Height: 2, HeaderHash: block.Header.Hash()},
+	} {
+		err := bs.SaveBlockWithCommit(next, invalid)
+		assert.True(errors.Is(err, store.ErrCommitMismatch), err)
+		assert.Equal(uint64(1), bs.Height())
+		_, err = bs.LoadBlock(2)
+		assert.True(errors.Is(err, store.ErrKeyNotFound), err)
+		_, err = bs.LoadCommit(2)
+		assert.True(errors.Is(err, store.ErrKeyNotFound), err)
+	}
+}
+
+// randomBlock returns block at given height, with nTxs random transactions.
+//
+// Header.ProposerAddress is random, so blocks at the same height have different hashes.
+// nTxs has to be positive, as empty slices are decoded as nil.
+func randomBlock(height uint64, nTxs int) *types.Block {
+	block := &types.Block{
+		Header: types.Header{
+			Height:          height,
+			ProposerAddress: randomBytes(20),
+		},
+		Data: types.Data{
+			Txs: make(types.Txs, nTxs),
+			IntermediateStateRoots: types.IntermediateStateRoots{
+				RawRootsList: make([][]byte, nTxs),
+			},
+		},
+	}
+	for i := 0; i < nTxs; i++ {
+		block.Data.Txs[i] = randomBytes(100)
+		block.Data.IntermediateStateRoots.RawRootsList[i] = randomBytes(32)
+	}
+	return block
+}
+
+func randomBytes(n int) []byte {
+	data := make([]byte, n)
+	_, _ = rand.Read(data)
+	return data
+}
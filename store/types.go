@@ -4,9 +4,19 @@ import "github.com/lazyledger/optimint/types"
 
 type BlockStore interface {
 	Height() uint64
+	// Base returns the lowest stored height, or 0 if store is empty.
+	Base() uint64
 
 	SaveBlock(block *types.Block) error
 
 	LoadBlock(height uint64) (*types.Block, error)
 	LoadBlockByHash(hash [32]byte) (*types.Block, error)
+
+	// SaveBlockWithCommit saves the block together with its commit, atomically.
+	SaveBlockWithCommit(block *types.Block, commit *types.Commit) error
+
+	// SaveCommit saves commit of the block at given height.
+	SaveCommit(height uint64, commit *types.Commit) error
+	// LoadCommit returns commit of the block at given height.
+	LoadCommit(height uint64) (*types.Commit, error)
 }
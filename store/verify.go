@@ -0,0 +1,42 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Verify checks all blocks stored in bs, from Base to Height. ErrStoreGap is returned if a block is missing or is
+// stored at wrong height, or if block following a block with commit has no commit.
+//
+// Unlike OpenBlockStore, which checks only the lowest and the highest stored block, Verify reads every stored block,
+// so it takes time proportional to the number of stored blocks.
+func Verify(bs BlockStore) error {
+	base, height := bs.Base(), bs.Height()
+	if height == 0 {
+		return nil
+	}
+	prevCommit := false
+	for h := base; h <= height; h++ {
+		block, err := bs.LoadBlock(h)
+		if errors.Is(err, ErrKeyNotFound) {
+			return fmt.Errorf("%w: missing block at height %d (stored %d-%d)", ErrStoreGap, h, base, height)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to load block at height %d: %w", h, err)
+		}
+		if block.Header.Height != h {
+			return fmt.Errorf("%w: block at height %d has height %d", ErrStoreGap, h, block.Header.Height)
+		}
+
+		_, err = bs.LoadCommit(h)
+		hasCommit := err == nil
+		if err != nil && !errors.Is(err, ErrKeyNotFound) {
+			return fmt.Errorf("failed to load commit at height %d: %w", h, err)
+		}
+		if prevCommit && !hasCommit {
+			return fmt.Errorf("%w: missing commit at height %d (stored %d-%d)", ErrStoreGap, h, base, height)
+		}
+		prevCommit = hasCommit
+	}
+	return nil
+}
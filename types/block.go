@@ -1,5 +1,10 @@
 package types
 
+import (
+	tmproto "github.com/lazyledger/lazyledger-core/proto/tendermint/types"
+	tmtypes "github.com/lazyledger/lazyledger-core/types"
+)
+
 type Header struct {
 	// Block and App version
 	Version Version
@@ -9,7 +14,7 @@ type Header struct {
 	NamespaceID [8]byte
 
 	Height uint64
-	Time   uint64 // time in tai64 format
+	Time   uint64 // time in nanoseconds since Unix epoch
 
 	// prev block info
 	LastHeaderHash [32]byte
@@ -68,3 +73,71 @@ type Signature []byte
 type IntermediateStateRoots struct {
 	RawRootsList [][]byte
 }
+
+// Copy returns a deep copy of the block, which can be modified without affecting the original.
+//
+// Tendermint evidence types are copied through their protobuf encoding (so their timestamps are in UTC).
+// Values of other evidence types (and evidence that can't be decoded, because it's invalid) are shared.
+func (b *Block) Copy() *Block {
+	if b == nil {
+		return nil
+	}
+	c := &Block{Header: b.Header}
+	c.Header.ProposerAddress = copyBytes(b.Header.ProposerAddress)
+	if b.Data.Txs != nil {
+		c.Data.Txs = make(Txs, len(b.Data.Txs))
+		for i, tx := range b.Data.Txs {
+			c.Data.Txs[i] = Tx(copyBytes(tx))
+		}
+	}
+	if b.Data.IntermediateStateRoots.RawRootsList != nil {
+		c.Data.IntermediateStateRoots.RawRootsList = make([][]byte, len(b.Data.IntermediateStateRoots.RawRootsList))
+		for i, root := range b.Data.IntermediateStateRoots.RawRootsList {
+			c.Data.IntermediateStateRoots.RawRootsList[i] = copyBytes(root)
+		}
+	}
+	if b.Data.Evidence.Evidence != nil {
+		c.Data.Evidence.Evidence = make([]Evidence, len(b.Data.Evidence.Evidence))
+		for i, ev := range b.Data.Evidence.Evidence {
+			c.Data.Evidence.Evidence[i] = copyEvidence(ev)
+		}
+	}
+	if b.LastCommit != nil {
+		c.LastCommit = &Commit{Height: b.LastCommit.Height, HeaderHash: b.LastCommit.HeaderHash}
+		if b.LastCommit.Signatures != nil {
+			c.LastCommit.Signatures = make([]Signature, len(b.LastCommit.Signatures))
+			for i, sig := range b.LastCommit.Signatures {
+				c.LastCommit.Signatures[i] = Signature(copyBytes(sig))
+			}
+		}
+	}
+	return c
+}
+
+func copyEvidence(ev Evidence) Evidence {
+	pb, err := tmtypes.EvidenceToProto(ev)
+	if err != nil {
+		return ev
+	}
+	// protobuf structs share byte slices with evidence, so they're encoded and decoded to get independent copy
+	bz, err := pb.Marshal()
+	if err != nil {
+		return ev
+	}
+	var decoded tmproto.Evidence
+	if err := decoded.Unmarshal(bz); err != nil {
+		return ev
+	}
+	c, err := tmtypes.EvidenceFromProto(&decoded)
+	if err != nil {
+		return ev
+	}
+	return c
+}
+
+func copyBytes(b []byte) []byte {
+	if b == nil {
+		return nil
+	}
+	return append([]byte{}, b...)
+}
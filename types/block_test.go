@@ -0,0 +1,65 @@
+package types
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	tmtypes "github.com/lazyledger/lazyledger-core/types"
+)
+
+func TestBlockCopy(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	newBlock := func() *Block {
+		return &Block{
+			Header: Header{
+				Height:          3,
+				LastHeaderHash:  [32]byte{1, 2, 3},
+				ProposerAddress: []byte("proposer"),
+			},
+			Data: Data{
+				Txs:                    Txs{Tx("tx1"), Tx("tx2")},
+				IntermediateStateRoots: IntermediateStateRoots{RawRootsList: [][]byte{{1}, {2}}},
+				Evidence:               EvidenceData{Evidence: []Evidence{testEvidence{height: 1, time: time.Unix(1, 0)}}},
+			},
+			LastCommit: &Commit{Height: 2, HeaderHash: [32]byte{1, 2, 3}, Signatures: []Signature{{1, 2}}},
+		}
+	}
+	original := newBlock()
+
+	c := original.Copy()
+	require.Equal(original, c)
+
+	c.Header.Height = 4
+	c.Header.LastHeaderHash[0] = 0xff
+	c.Header.ProposerAddress[0] = 'x'
+	c.Data.Txs[0][0] = 'x'
+	c.Data.Txs = append(c.Data.Txs, Tx("tx3"))
+	c.Data.IntermediateStateRoots.RawRootsList[0][0] = 0xff
+	c.Data.Evidence.Evidence[0] = testEvidence{height: 2}
+	c.LastCommit.HeaderHash[0] = 0xff
+	c.LastCommit.Signatures[0][0] = 0xff
+
+	assert.Equal(newBlock(), original)
+
+	// tendermint evidence is copied as well
+	duplicateVote := tmtypes.NewMockDuplicateVoteEvidence(1, time.Unix(1, 0).UTC(), "TestChain")
+	original = &Block{Data: Data{Evidence: EvidenceData{Evidence: []Evidence{duplicateVote}}}}
+	c = original.Copy()
+	require.Equal(original, c)
+	copied, ok := c.Data.Evidence.Evidence[0].(*tmtypes.DuplicateVoteEvidence)
+	require.True(ok)
+	require.NotSame(duplicateVote, copied)
+	signature := append([]byte{}, duplicateVote.VoteA.Signature...)
+	copied.VoteA.Signature[0] ^= 0xff
+	copied.VoteB.Height = 2
+	assert.Equal(signature, duplicateVote.VoteA.Signature)
+	assert.Equal(int64(1), duplicateVote.VoteB.Height)
+
+	assert.Nil((*Block)(nil).Copy())
+	assert.Equal(&Block{}, (&Block{}).Copy())
+}
@@ -0,0 +1,33 @@
+package types
+
+// Codec encodes and decodes blocks and commits, for storage or transmission.
+//
+// Codec allows embedders to use a format of choice for their store or DA layer.
+type Codec interface {
+	MarshalBlock(block *Block) ([]byte, error)
+	UnmarshalBlock(data []byte, block *Block) error
+
+	MarshalCommit(commit *Commit) ([]byte, error)
+	UnmarshalCommit(data []byte, commit *Commit) error
+}
+
+// DefaultCodec encodes objects into their binary form (see MarshalBinary and UnmarshalBinary).
+var DefaultCodec Codec = binaryCodec{}
+
+type binaryCodec struct{}
+
+func (binaryCodec) MarshalBlock(block *Block) ([]byte, error) {
+	return block.MarshalBinary()
+}
+
+func (binaryCodec) UnmarshalBlock(data []byte, block *Block) error {
+	return block.UnmarshalBinary(data)
+}
+
+func (binaryCodec) MarshalCommit(commit *Commit) ([]byte, error) {
+	return commit.MarshalBinary()
+}
+
+func (binaryCodec) UnmarshalCommit(data []byte, commit *Commit) error {
+	return commit.UnmarshalBinary(data)
+}
@@ -0,0 +1,42 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+
+	tmbytes "github.com/lazyledger/lazyledger-core/libs/bytes"
+	tmtypes "github.com/lazyledger/lazyledger-core/types"
+)
+
+// ErrUnsupportedEvidence is returned when evidence type can't be encoded into DA layer shares.
+var ErrUnsupportedEvidence = errors.New("unsupported evidence type")
+
+// DataAvailabilityHeader returns row and column roots of the erasure coded block data, as computed by lazyledger DA layer.
+//
+// Data is split into namespaced shares (transactions, intermediate state roots and evidence use reserved namespaces),
+// arranged in a square and extended with Reed-Solomon encoding. Every row and column of extended square is committed
+// with namespaced Merkle tree. DataAvailabilityHeader.Hash returns a single root over all row and column roots, which
+// can be verified by data availability sampling.
+func (d *Data) DataAvailabilityHeader() (*tmtypes.DataAvailabilityHeader, error) {
+	txs := make([]tmtypes.Tx, len(d.Txs))
+	for i := range d.Txs {
+		txs[i] = tmtypes.Tx(d.Txs[i])
+	}
+	roots := make([]tmbytes.HexBytes, len(d.IntermediateStateRoots.RawRootsList))
+	for i := range d.IntermediateStateRoots.RawRootsList {
+		roots[i] = d.IntermediateStateRoots.RawRootsList[i]
+	}
+	evidence := make([]tmtypes.Evidence, len(d.Evidence.Evidence))
+	for i, ev := range d.Evidence.Evidence {
+		// only those evidence types can be encoded into shares
+		switch ev.(type) {
+		case *tmtypes.DuplicateVoteEvidence, *tmtypes.LightClientAttackEvidence:
+			evidence[i] = ev
+		default:
+			return nil, fmt.Errorf("%w: %T", ErrUnsupportedEvidence, ev)
+		}
+	}
+
+	block := tmtypes.MakeBlock(0, txs, evidence, roots, tmtypes.Messages{}, nil)
+	return &block.DataAvailabilityHeader, nil
+}
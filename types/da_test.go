@@ -0,0 +1,68 @@
+package types
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"testing"
+	"time"
+
+	abci "github.com/lazyledger/lazyledger-core/abci/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeEvidence is an evidence type that can't be encoded into DA layer shares.
+type fakeEvidence struct{}
+
+func (fakeEvidence) ABCI() []abci.Evidence { return nil }
+func (fakeEvidence) Bytes() []byte         { return nil }
+func (fakeEvidence) Hash() []byte          { return nil }
+func (fakeEvidence) Height() int64         { return 0 }
+func (fakeEvidence) String() string        { return "fake" }
+func (fakeEvidence) Time() time.Time       { return time.Time{} }
+func (fakeEvidence) ValidateBasic() error  { return nil }
+
+func TestDataAvailabilityHeader(t *testing.T) {
+	largeTxs := make(Txs, 10)
+	for i := range largeTxs {
+		largeTxs[i] = bytes.Repeat([]byte{byte(i)}, 300)
+	}
+
+	cases := []struct {
+		name         string
+		data         *Data
+		expectedRows int
+		expectedHash string
+	}{
+		// no shares, hash of empty tree
+		{"empty", &Data{}, 0, "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"},
+		{"txs and roots", &Data{
+			Txs:                    Txs{Tx("tx1"), Tx("tx2")},
+			IntermediateStateRoots: IntermediateStateRoots{RawRootsList: [][]byte{{1, 2, 3}, {4, 5, 6}}},
+		}, 4, "3cabf6ad24a997d062f8129c515fa774b34b121aeb12d0f6b63f6268a1ec90ad"},
+		{"multiple shares", &Data{Txs: largeTxs}, 10, "d42492131be9c657f23c4069e30c2a86f0f1eb8104b565739027cd405d3f12f7"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			dah, err := c.data.DataAvailabilityHeader()
+			require.NoError(err)
+			require.NotNil(dah)
+			assert.Len(dah.RowsRoots, c.expectedRows)
+			assert.Len(dah.ColumnRoots, c.expectedRows)
+			assert.Equal(c.expectedHash, hex.EncodeToString(dah.Hash()))
+
+			// deterministic
+			again, err := c.data.DataAvailabilityHeader()
+			require.NoError(err)
+			assert.Equal(dah.Hash(), again.Hash())
+		})
+	}
+
+	_, err := (&Data{Evidence: EvidenceData{Evidence: []Evidence{fakeEvidence{}}}}).DataAvailabilityHeader()
+	assert.True(t, errors.Is(err, ErrUnsupportedEvidence))
+}
@@ -0,0 +1,41 @@
+package types
+
+import (
+	"fmt"
+
+	"github.com/lazyledger/lazyledger-core/crypto/merkle"
+	"github.com/minio/sha256-simd"
+)
+
+// Hash returns hash of the header.
+//
+// Header contains only fields that can always be serialized, so Hash panics if serialization fails, instead of
+// returning a hash that doesn't identify the header.
+func (h *Header) Hash() [32]byte {
+	data, err := h.MarshalBinary()
+	if err != nil {
+		panic(fmt.Sprintf("failed to serialize header: %v", err))
+	}
+	return sha256.Sum256(data)
+}
+
+// Hash returns Merkle root of block transactions, used as Header.DataHash.
+//
+// Leaves of the tree are transaction hashes (see Tx.Hash). Tree is constructed as described in RFC 6962.
+func (d *Data) Hash() [32]byte {
+	leaves := make([][]byte, len(d.Txs))
+	for i := range d.Txs {
+		hash := d.Txs[i].Hash()
+		leaves[i] = hash[:]
+	}
+	var root [32]byte
+	copy(root[:], merkle.HashFromByteSlices(leaves))
+	return root
+}
+
+// Hash returns SHA-256 hash of the transaction bytes.
+//
+// It's the canonical transaction identifier, used by mempool cache and RPC.
+func (tx Tx) Hash() [32]byte {
+	return sha256.Sum256(tx)
+}
@@ -0,0 +1,51 @@
+package types
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	tmtypes "github.com/lazyledger/lazyledger-core/types"
+)
+
+func TestTxHash(t *testing.T) {
+	cases := []struct {
+		name     string
+		tx       Tx
+		expected string
+	}{
+		// >>> sha256(b'').hexdigest()
+		{"empty", Tx{}, "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"},
+		// >>> sha256(b'abc').hexdigest()
+		{"abc", Tx("abc"), "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad"},
+		// >>> sha256(b'\x00').hexdigest()
+		{"zero byte", Tx{0x00}, "6e340b9cffb37a989ca544e6bb780a2c78901d3fb33738768511a30617afa01d"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			hash := c.tx.Hash()
+			assert.Equal(c.expected, hex.EncodeToString(hash[:]))
+			// must match hash used by Tendermint types (and RPC clients)
+			assert.Equal(tmtypes.Tx(c.tx).Hash(), hash[:])
+		})
+	}
+}
+
+func TestDataHash(t *testing.T) {
+	assert := assert.New(t)
+
+	txs := Txs{Tx("tx1"), Tx("tx2"), Tx("tx3")}
+	tmTxs := tmtypes.Txs{tmtypes.Tx("tx1"), tmtypes.Tx("tx2"), tmtypes.Tx("tx3")}
+
+	data := &Data{Txs: txs}
+	hash := data.Hash()
+	// must match Tendermint definition of transactions Merkle root
+	assert.Equal(tmTxs.Hash(), hash[:])
+
+	data.Txs[1] = Tx("evil")
+	assert.NotEqual(hash, data.Hash())
+}
@@ -0,0 +1,41 @@
+package types
+
+import (
+	"encoding/binary"
+
+	"github.com/minio/sha256-simd"
+)
+
+// ChainNamespaceID returns namespace ID derived from chain ID: first 8 bytes of SHA-256 hash of chain ID.
+//
+// It's used when namespace ID is not configured explicitly, so all nodes of given chain agree on namespace.
+func ChainNamespaceID(chainID string) [8]byte {
+	hash := sha256.Sum256([]byte(chainID))
+	var nID [8]byte
+	copy(nID[:], hash[:])
+	return nID
+}
+
+// EpochNamespaceID returns namespace ID used for block at given height.
+//
+// If epochLength is zero, namespace is static and base is returned. Otherwise, heights are grouped into epochs
+// of epochLength blocks (starting from height 1), and namespace ID for each epoch is derived from base and epoch number.
+func EpochNamespaceID(base [8]byte, epochLength uint64, height uint64) [8]byte {
+	if epochLength == 0 {
+		return base
+	}
+
+	var epoch uint64
+	if height > 0 {
+		epoch = (height - 1) / epochLength
+	}
+
+	data := make([]byte, len(base)+8)
+	copy(data, base[:])
+	binary.BigEndian.PutUint64(data[len(base):], epoch)
+	hash := sha256.Sum256(data)
+
+	var nID [8]byte
+	copy(nID[:], hash[:])
+	return nID
+}
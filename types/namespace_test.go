@@ -0,0 +1,44 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEpochNamespaceID(t *testing.T) {
+	assert := assert.New(t)
+
+	base := [8]byte{1, 2, 3, 4, 5, 6, 7, 8}
+
+	// static namespace
+	for _, h := range []uint64{1, 2, 100, 1 << 40} {
+		assert.Equal(base, EpochNamespaceID(base, 0, h))
+	}
+
+	// rotation every 10 blocks
+	first := EpochNamespaceID(base, 10, 1)
+	second := EpochNamespaceID(base, 10, 11)
+	assert.NotEqual(first, second)
+	assert.NotEqual(base, first)
+	for h := uint64(1); h <= 10; h++ {
+		assert.Equal(first, EpochNamespaceID(base, 10, h))
+	}
+	for h := uint64(11); h <= 20; h++ {
+		assert.Equal(second, EpochNamespaceID(base, 10, h))
+	}
+
+	// different chains never share namespace in the same epoch
+	assert.NotEqual(first, EpochNamespaceID([8]byte{8, 7, 6, 5, 4, 3, 2, 1}, 10, 1))
+}
+
+func TestChainNamespaceID(t *testing.T) {
+	assert := assert.New(t)
+
+	nID := ChainNamespaceID("optimint-1")
+	// derived namespace ID is part of the protocol, it has to be stable
+	assert.Equal([8]byte{0x37, 0x27, 0x87, 0xe2, 0xd9, 0x82, 0x54, 0x74}, nID)
+	assert.Equal(nID, ChainNamespaceID("optimint-1"))
+	assert.NotEqual(nID, ChainNamespaceID("optimint-2"))
+	assert.NotEqual([8]byte{}, ChainNamespaceID(""))
+}
@@ -0,0 +1,57 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/lazyledger/lazyledger-core/crypto/merkle"
+)
+
+var (
+	// ErrTxIndexOutOfRange is returned when proof is requested for non-existing transaction.
+	ErrTxIndexOutOfRange = errors.New("transaction index out of range")
+	// ErrInvalidTxProof is returned when transaction inclusion proof doesn't verify against data hash.
+	ErrInvalidTxProof = errors.New("invalid transaction inclusion proof")
+)
+
+// TxProof is a Merkle proof of inclusion of a transaction in block Data.
+type TxProof struct {
+	RootHash [32]byte
+	Tx       Tx
+	Proof    merkle.Proof
+}
+
+// TxProof returns proof of inclusion of i-th transaction, against Data.Hash (Header.DataHash).
+func (d *Data) TxProof(i int) (*TxProof, error) {
+	if i < 0 || i >= len(d.Txs) {
+		return nil, fmt.Errorf("%w: %d (txs: %d)", ErrTxIndexOutOfRange, i, len(d.Txs))
+	}
+	leaves := make([][]byte, len(d.Txs))
+	for j := range d.Txs {
+		hash := d.Txs[j].Hash()
+		leaves[j] = hash[:]
+	}
+	root, proofs := merkle.ProofsFromByteSlices(leaves)
+
+	proof := &TxProof{
+		Tx:    d.Txs[i],
+		Proof: *proofs[i],
+	}
+	copy(proof.RootHash[:], root)
+	return proof, nil
+}
+
+// Validate checks if proof is valid against given data hash (usually Header.DataHash).
+func (p *TxProof) Validate(dataHash [32]byte) error {
+	if p.RootHash != dataHash {
+		return fmt.Errorf("%w: root hash doesn't match data hash", ErrInvalidTxProof)
+	}
+	if err := p.Proof.ValidateBasic(); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidTxProof, err)
+	}
+	leaf := p.Tx.Hash()
+	if err := p.Proof.Verify(p.RootHash[:], leaf[:]); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidTxProof, err)
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTxProof(t *testing.T) {
+	data := &Data{Txs: Txs{Tx("tx1"), Tx("tx2"), Tx("tx3"), Tx("tx4"), Tx("tx5")}}
+	dataHash := data.Hash()
+
+	// valid proofs for all transactions
+	for i := range data.Txs {
+		proof, err := data.TxProof(i)
+		require.NoError(t, err)
+		assert.Equal(t, data.Txs[i], proof.Tx)
+		assert.NoError(t, proof.Validate(dataHash))
+	}
+
+	_, err := data.TxProof(len(data.Txs))
+	assert.ErrorIs(t, err, ErrTxIndexOutOfRange)
+	_, err = data.TxProof(-1)
+	assert.ErrorIs(t, err, ErrTxIndexOutOfRange)
+
+	cases := []struct {
+		name  string
+		forge func(p *TxProof)
+		hash  [32]byte
+	}{
+		{"forged tx", func(p *TxProof) { p.Tx = Tx("evil") }, dataHash},
+		{"other tx", func(p *TxProof) { p.Tx = data.Txs[0] }, dataHash},
+		{"other data hash", func(p *TxProof) {}, [32]byte{1, 2, 3}},
+		{"forged root", func(p *TxProof) { p.RootHash = [32]byte{1, 2, 3} }, [32]byte{1, 2, 3}},
+		{"forged aunts", func(p *TxProof) { p.Proof.Aunts[0] = make([]byte, 32) }, dataHash},
+		{"forged index", func(p *TxProof) { p.Proof.Index = 3 }, dataHash},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			proof, err := data.TxProof(2)
+			require.NoError(t, err)
+			c.forge(proof)
+			assert.ErrorIs(t, proof.Validate(c.hash), ErrInvalidTxProof)
+		})
+	}
+}
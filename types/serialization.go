@@ -0,0 +1,67 @@
+package types
+
+import (
+	"bytes"
+	"encoding/gob"
+)
+
+// TODO: replace gob with protobuf (see ADR-005).
+
+// block and header are used to avoid infinite recursion, as gob uses encoding.BinaryMarshaler if implemented.
+type block Block
+type header Header
+type commit Commit
+
+// MarshalBinary encodes Block into binary form and returns it.
+func (b *Block) MarshalBinary() ([]byte, error) {
+	return gobEncode((*block)(b))
+}
+
+// UnmarshalBinary decodes binary form of Block into object.
+func (b *Block) UnmarshalBinary(data []byte) error {
+	return gobDecode(data, (*block)(b))
+}
+
+// Size returns the length of binary form of Block (see MarshalBinary).
+//
+// Size is recomputed on every call, as block can be modified. Zero is returned if block can't be serialized.
+func (b *Block) Size() int {
+	data, err := b.MarshalBinary()
+	if err != nil {
+		return 0
+	}
+	return len(data)
+}
+
+// MarshalBinary encodes Header into binary form and returns it.
+func (h *Header) MarshalBinary() ([]byte, error) {
+	return gobEncode((*header)(h))
+}
+
+// UnmarshalBinary decodes binary form of Header into object.
+func (h *Header) UnmarshalBinary(data []byte) error {
+	return gobDecode(data, (*header)(h))
+}
+
+// MarshalBinary encodes Commit into binary form and returns it.
+func (c *Commit) MarshalBinary() ([]byte, error) {
+	return gobEncode((*commit)(c))
+}
+
+// UnmarshalBinary decodes binary form of Commit into object.
+func (c *Commit) UnmarshalBinary(data []byte) error {
+	return gobDecode(data, (*commit)(c))
+}
+
+func gobEncode(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	err := gob.NewEncoder(&buf).Encode(v)
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func gobDecode(data []byte, v interface{}) error {
+	return gob.NewDecoder(bytes.NewReader(data)).Decode(v)
+}
@@ -0,0 +1,59 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlockSize(t *testing.T) {
+	cases := []struct {
+		name  string
+		block *Block
+	}{
+		{"empty", &Block{}},
+		{"header only", &Block{Header: Header{Height: 7, Time: 12345, ProposerAddress: []byte("proposer")}}},
+		{"with txs", &Block{
+			Header: Header{Height: 8},
+			Data: Data{
+				Txs:                    Txs{Tx("tx1"), Tx("a longer transaction")},
+				IntermediateStateRoots: IntermediateStateRoots{RawRootsList: [][]byte{{1, 2, 3}}},
+			},
+			LastCommit: &Commit{Height: 7, HeaderHash: [32]byte{1}, Signatures: []Signature{{4, 5, 6}}},
+		}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			data, err := c.block.MarshalBinary()
+			require.NoError(err)
+			assert.Equal(len(data), c.block.Size())
+		})
+	}
+}
+
+func TestDefaultCodec(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	commit := &Commit{Height: 3, HeaderHash: [32]byte{1, 2}, Signatures: []Signature{{4, 5, 6}, {7}}}
+	data, err := DefaultCodec.MarshalCommit(commit)
+	require.NoError(err)
+	var decodedCommit Commit
+	require.NoError(DefaultCodec.UnmarshalCommit(data, &decodedCommit))
+	assert.Equal(commit, &decodedCommit)
+
+	block := &Block{Header: Header{Height: 4}, Data: Data{Txs: Txs{Tx("tx")}}, LastCommit: commit}
+	data, err = DefaultCodec.MarshalBlock(block)
+	require.NoError(err)
+	binary, err := block.MarshalBinary()
+	require.NoError(err)
+	assert.Equal(binary, data)
+	var decodedBlock Block
+	require.NoError(DefaultCodec.UnmarshalBlock(data, &decodedBlock))
+	assert.Equal(block, &decodedBlock)
+}
@@ -0,0 +1,102 @@
+package types
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lazyledger/lazyledger-core/crypto"
+	tmproto "github.com/lazyledger/lazyledger-core/proto/tendermint/types"
+)
+
+var (
+	// ErrCommitHeightMismatch is returned when commit height is different than height of the header.
+	ErrCommitHeightMismatch = errors.New("commit height doesn't match header height")
+	// ErrCommitHashMismatch is returned when commit doesn't refer to the header hash.
+	ErrCommitHashMismatch = errors.New("commit hash doesn't match header hash")
+	// ErrNoSignatures is returned when commit doesn't contain any signature.
+	ErrNoSignatures = errors.New("commit doesn't contain signatures")
+	// ErrInvalidSignature is returned when commit is not signed by the aggregator.
+	ErrInvalidSignature = errors.New("invalid aggregator signature")
+	// ErrInvalidProposer is returned when header is proposed by someone else than the aggregator.
+	ErrInvalidProposer = errors.New("header proposer is not the aggregator")
+
+	// ErrBlockTooLarge is returned when block size exceeds consensus params.
+	ErrBlockTooLarge = errors.New("block exceeds max bytes")
+	// ErrBlockGasExceeded is returned when gas of block transactions exceeds consensus params.
+	ErrBlockGasExceeded = errors.New("block exceeds max gas")
+	// ErrEvidenceTooLarge is returned when size of block evidence exceeds consensus params.
+	ErrEvidenceTooLarge = errors.New("block evidence exceeds max bytes")
+	// ErrEvidenceExpired is returned when block contains evidence older than allowed by consensus params.
+	ErrEvidenceExpired = errors.New("block evidence expired")
+)
+
+// ValidateCommit checks if commit is valid for given header and signed by the aggregator.
+//
+// Aggregator public key should be taken from genesis, so block authenticity doesn't depend on who delivers
+// the block. If header contains ProposerAddress, it has to match the aggregator address as well.
+func ValidateCommit(header *Header, commit *Commit, aggregator crypto.PubKey) error {
+	if commit.Height != header.Height {
+		return ErrCommitHeightMismatch
+	}
+	hash := header.Hash()
+	if commit.HeaderHash != hash {
+		return ErrCommitHashMismatch
+	}
+	if len(header.ProposerAddress) > 0 && !bytes.Equal(header.ProposerAddress, aggregator.Address()) {
+		return ErrInvalidProposer
+	}
+	if len(commit.Signatures) == 0 {
+		return ErrNoSignatures
+	}
+	if !aggregator.VerifySignature(hash[:], commit.Signatures[0]) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// GasFunc returns gas wanted by transaction.
+type GasFunc func(tx Tx) int64
+
+// ValidateParams checks if block respects consensus params.
+//
+// Block size (see Block.Size) has to be within Block.MaxBytes and total size of evidence within Evidence.MaxBytes.
+// Evidence is expired (and rejected) if it's older than both Evidence.MaxAgeNumBlocks blocks and
+// Evidence.MaxAgeDuration; block time is interpreted as nanoseconds since Unix epoch. Block doesn't contain gas
+// of transactions, so gas is checked against Block.MaxGas only if txGas is provided. Non-positive limits are not
+// enforced.
+func (b *Block) ValidateParams(params tmproto.ConsensusParams, txGas GasFunc) error {
+	if params.Block.MaxBytes > 0 {
+		if size := int64(b.Size()); size > params.Block.MaxBytes {
+			return fmt.Errorf("%w: %d > %d", ErrBlockTooLarge, size, params.Block.MaxBytes)
+		}
+	}
+
+	if params.Block.MaxGas > 0 && txGas != nil {
+		var gas int64
+		for _, tx := range b.Data.Txs {
+			gas += txGas(tx)
+			if gas > params.Block.MaxGas {
+				return fmt.Errorf("%w: %d > %d", ErrBlockGasExceeded, gas, params.Block.MaxGas)
+			}
+		}
+	}
+
+	var evidenceBytes int64
+	blockTime := time.Unix(0, int64(b.Header.Time))
+	for _, ev := range b.Data.Evidence.Evidence {
+		evidenceBytes += int64(len(ev.Bytes()))
+		ageBlocks := int64(b.Header.Height) - ev.Height()
+		ageDuration := blockTime.Sub(ev.Time())
+		if params.Evidence.MaxAgeNumBlocks > 0 && ageBlocks > params.Evidence.MaxAgeNumBlocks &&
+			params.Evidence.MaxAgeDuration > 0 && ageDuration > params.Evidence.MaxAgeDuration {
+			return fmt.Errorf("%w: evidence %X from height %d, age %d blocks, %s",
+				ErrEvidenceExpired, ev.Hash(), ev.Height(), ageBlocks, ageDuration)
+		}
+	}
+	if params.Evidence.MaxBytes > 0 && evidenceBytes > params.Evidence.MaxBytes {
+		return fmt.Errorf("%w: %d > %d", ErrEvidenceTooLarge, evidenceBytes, params.Evidence.MaxBytes)
+	}
+	return nil
+}
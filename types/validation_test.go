@@ -0,0 +1,153 @@
+package types
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	abci "github.com/lazyledger/lazyledger-core/abci/types"
+	"github.com/lazyledger/lazyledger-core/crypto/ed25519"
+	tmproto "github.com/lazyledger/lazyledger-core/proto/tendermint/types"
+)
+
+func TestValidateCommit(t *testing.T) {
+	t.Parallel()
+
+	aggregator := ed25519.GenPrivKey()
+	rogue := ed25519.GenPrivKey()
+
+	header := &Header{
+		Height:          10,
+		ProposerAddress: aggregator.PubKey().Address(),
+	}
+	hash := header.Hash()
+
+	rogueHeader := &Header{
+		Height:          10,
+		ProposerAddress: rogue.PubKey().Address(),
+	}
+	rogueHash := rogueHeader.Hash()
+
+	sign := func(t *testing.T, key ed25519.PrivKey, hash [32]byte) Signature {
+		sig, err := key.Sign(hash[:])
+		require.NoError(t, err)
+		return sig
+	}
+
+	cases := []struct {
+		name     string
+		header   *Header
+		commit   *Commit
+		expected error
+	}{
+		{"valid", header,
+			&Commit{Height: 10, HeaderHash: hash, Signatures: []Signature{sign(t, aggregator, hash)}},
+			nil},
+		{"rogue signer", header,
+			&Commit{Height: 10, HeaderHash: hash, Signatures: []Signature{sign(t, rogue, hash)}},
+			ErrInvalidSignature},
+		{"rogue proposer", rogueHeader,
+			&Commit{Height: 10, HeaderHash: rogueHash, Signatures: []Signature{sign(t, rogue, rogueHash)}},
+			ErrInvalidProposer},
+		{"no signatures", header,
+			&Commit{Height: 10, HeaderHash: hash},
+			ErrNoSignatures},
+		{"wrong height", header,
+			&Commit{Height: 9, HeaderHash: hash, Signatures: []Signature{sign(t, aggregator, hash)}},
+			ErrCommitHeightMismatch},
+		{"wrong hash", header,
+			&Commit{Height: 10, HeaderHash: [32]byte{1, 2, 3}, Signatures: []Signature{sign(t, aggregator, hash)}},
+			ErrCommitHashMismatch},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := ValidateCommit(c.header, c.commit, aggregator.PubKey())
+			if c.expected == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, c.expected)
+			}
+		})
+	}
+}
+
+// testEvidence is a minimal Evidence implementation with given height, time and size.
+type testEvidence struct {
+	height int64
+	time   time.Time
+	size   int
+}
+
+func (e testEvidence) ABCI() []abci.Evidence { return nil }
+func (e testEvidence) Bytes() []byte         { return make([]byte, e.size) }
+func (e testEvidence) Hash() []byte          { return []byte{byte(e.height)} }
+func (e testEvidence) Height() int64         { return e.height }
+func (e testEvidence) String() string        { return "testEvidence" }
+func (e testEvidence) Time() time.Time       { return e.time }
+func (e testEvidence) ValidateBasic() error  { return nil }
+
+func TestValidateParams(t *testing.T) {
+	t.Parallel()
+
+	blockTime := time.Unix(1000, 0)
+	newBlock := func(txs int, evidence ...Evidence) *Block {
+		block := &Block{Header: Header{Height: 100, Time: uint64(blockTime.UnixNano())}}
+		for i := 0; i < txs; i++ {
+			block.Data.Txs = append(block.Data.Txs, make(Tx, 100))
+		}
+		block.Data.Evidence.Evidence = evidence
+		return block
+	}
+	params := tmproto.ConsensusParams{
+		Block: tmproto.BlockParams{MaxBytes: 2000, MaxGas: 30},
+		Evidence: tmproto.EvidenceParams{
+			MaxAgeNumBlocks: 10,
+			MaxAgeDuration:  time.Minute,
+			MaxBytes:        100,
+		},
+	}
+	txGas := func(tx Tx) int64 { return 10 }
+
+	cases := []struct {
+		name     string
+		block    *Block
+		params   tmproto.ConsensusParams
+		txGas    GasFunc
+		expected error
+	}{
+		{"valid", newBlock(3), params, txGas, nil},
+		{"too large", newBlock(20), params, nil, ErrBlockTooLarge},
+		{"max bytes not enforced", newBlock(20),
+			tmproto.ConsensusParams{Block: tmproto.BlockParams{MaxBytes: -1}}, nil, nil},
+		{"gas exceeded", newBlock(4), params, txGas, ErrBlockGasExceeded},
+		{"gas unknown", newBlock(4), params, nil, nil},
+		{"gas not enforced", newBlock(4),
+			tmproto.ConsensusParams{Block: tmproto.BlockParams{MaxGas: -1}}, txGas, nil},
+		{"evidence within limits", newBlock(1,
+			testEvidence{height: 95, time: blockTime.Add(-time.Hour), size: 50},
+			testEvidence{height: 50, time: blockTime.Add(-time.Second), size: 50}),
+			params, txGas, nil},
+		{"evidence too large", newBlock(1,
+			testEvidence{height: 95, time: blockTime, size: 60},
+			testEvidence{height: 95, time: blockTime, size: 60}),
+			params, txGas, ErrEvidenceTooLarge},
+		{"evidence expired", newBlock(1,
+			testEvidence{height: 50, time: blockTime.Add(-time.Hour), size: 10}),
+			params, txGas, ErrEvidenceExpired},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			err := c.block.ValidateParams(c.params, c.txGas)
+			if c.expected == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, c.expected)
+			}
+		})
+	}
+}